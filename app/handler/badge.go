@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go-readme-stats/app/stats"
+	"go-readme-stats/app/svg"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	shieldsSchemaVersion = 1
+	notFoundColor        = "lightgrey"
+)
+
+// shieldsResponse is the shields.io endpoint badge schema, see https://shields.io/badges/endpoint-badge.
+type shieldsResponse struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+	IsError       bool   `json:"isError,omitempty"`
+	CacheSeconds  int    `json:"cacheSeconds"`
+}
+
+// badgeOptions picks the language a badge shows.
+type badgeOptions struct {
+	Lang  string // Language name, matched case-insensitively, empty to pick by Rank
+	Rank  int    // 1 for the top language
+	Label string
+}
+
+// GetBadge serves a shields.io endpoint badge for the top language, the language at rank= or the one named by lang=.
+func GetBadge(c *gin.Context) {
+	opts, err := parseBadgeStatsOptions(c)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid parameters: "+err.Error())
+		return
+	}
+
+	badgeOpts, err := parseBadgeOptions(c)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid parameters: "+err.Error())
+		return
+	}
+
+	languages, err := FetchStats(ignoredLanguages, opts)
+	if err != nil {
+		log.Printf("Error: Failed to fetch stats for request %s: %v", c.Request.URL.String(), err)
+		c.String(http.StatusInternalServerError, "Error fetching stats")
+		return
+	}
+
+	response := shieldsResponse{
+		SchemaVersion: shieldsSchemaVersion,
+		Label:         badgeOpts.Label,
+		Message:       "not found",
+		Color:         notFoundColor,
+		IsError:       true,
+		CacheSeconds:  cacheMaxAge,
+	}
+
+	// Shields renders errors as a badge too, so a missing language is still a successful response
+	if lang, found := selectBadgeLang(languages, badgeOpts); found {
+		response.Message = badgeMessage(lang)
+		response.Color = strings.TrimPrefix(lang.Colour, "#")
+		response.IsError = false
+	}
+
+	c.Header("Cache-Control", cacheControl)
+	c.JSON(http.StatusOK, response)
+}
+
+// GetBadgeSVG renders a badge for the same language as GetBadge, labelled with the language name and showing its share.
+// A language that isn't found gives a grey "not found" badge, so READMEs don't show a broken image.
+func GetBadgeSVG(c *gin.Context) {
+	opts, err := parseBadgeStatsOptions(c)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid parameters: "+err.Error())
+		return
+	}
+
+	badgeOpts, err := parseBadgeOptions(c)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid parameters: "+err.Error())
+		return
+	}
+
+	svgOpts := svg.DefaultBadgeOptions()
+	svgOpts.Style = c.DefaultQuery("style", svgOpts.Style)
+	svgOpts.Theme = c.DefaultQuery("theme", svgOpts.Theme)
+	svgOpts.Colours = svg.Theme{Background: c.Query("bg_color"), Text: c.Query("text_color")}
+	if err := svgOpts.Validate(); err != nil {
+		c.String(http.StatusBadRequest, "Invalid parameters: "+err.Error())
+		return
+	}
+
+	languages, err := FetchStats(ignoredLanguages, opts)
+	if err != nil {
+		log.Printf("Error: Failed to fetch stats for request %s: %v", c.Request.URL.String(), err)
+		c.String(http.StatusInternalServerError, "Error fetching stats")
+		return
+	}
+
+	svgOpts.Label, svgOpts.Message = badgeOpts.Label, "not found"
+	if lang, found := selectBadgeLang(languages, badgeOpts); found {
+		svgOpts.Message = formatPercent(lang.Percent)
+		svgOpts.Colour = lang.Colour
+		if c.Query("label") == "" {
+			svgOpts.Label = lang.Name
+		}
+	}
+
+	svgContent, err := GenerateBadge(svgOpts)
+	if err != nil {
+		log.Printf("Error: Failed to generate badge for request %s: %v", c.Request.URL.String(), err)
+		c.String(http.StatusInternalServerError, "Error generating SVG")
+		return
+	}
+
+	c.Header("Content-Type", "image/svg+xml")
+	c.Header("Cache-Control", cacheControl)
+	c.String(http.StatusOK, svgContent)
+}
+
+// parseBadgeStatsOptions reads the same scoring parameters as the card, but keeps as many languages as
+// possible apart when langs_count isn't set, so lang= and rank= can reach those the card folds into "Other".
+func parseBadgeStatsOptions(c *gin.Context) (stats.Options, error) {
+	opts, err := parseStatsOptions(c)
+	if _, ok := c.GetQuery("langs_count"); !ok {
+		opts.LangsCount = stats.MaxLangsCount
+	}
+
+	return opts, err
+}
+
+// parseBadgeOptions reads lang, rank and label, defaulting to the top language.
+func parseBadgeOptions(c *gin.Context) (badgeOptions, error) {
+	opts := badgeOptions{Lang: c.Query("lang"), Rank: 1}
+	if err := queryInt(c, "rank", &opts.Rank); err != nil {
+		return opts, err
+	}
+
+	if opts.Rank < 1 || opts.Rank > stats.MaxLangsCount {
+		return opts, fmt.Errorf("rank must be between 1 and %d", stats.MaxLangsCount)
+	}
+
+	if _, ok := c.GetQuery("rank"); ok && opts.Lang != "" {
+		return opts, errors.New("lang and rank can't be combined")
+	}
+
+	switch {
+	case c.Query("label") != "":
+		opts.Label = c.Query("label")
+	case opts.Lang != "":
+		opts.Label = "language"
+	case opts.Rank == 1:
+		opts.Label = "top language"
+	default:
+		opts.Label = "language #" + strconv.Itoa(opts.Rank)
+	}
+
+	return opts, nil
+}
+
+// selectBadgeLang returns the language named by opts.Lang, or else the one at opts.Rank.
+// The "Other" entry is never picked, since it isn't a language.
+func selectBadgeLang(languages []stats.Lang, opts badgeOptions) (stats.Lang, bool) {
+	rank := 0
+	for _, lang := range languages {
+		if lang.IsOther() {
+			continue
+		}
+
+		if opts.Lang != "" {
+			if strings.EqualFold(lang.Name, opts.Lang) {
+				return lang, true
+			}
+			continue
+		}
+
+		if rank++; rank == opts.Rank {
+			return lang, true
+		}
+	}
+
+	return stats.Lang{}, false
+}
+
+// badgeMessage returns the language and its share, e.g. "Go 45.5%".
+func badgeMessage(lang stats.Lang) string {
+	return lang.Name + " " + formatPercent(lang.Percent)
+}
+
+// formatPercent returns a percentage with as many decimals as it was rounded to, e.g. "45.5%".
+func formatPercent(percent float64) string {
+	return strconv.FormatFloat(percent, 'f', -1, 64) + "%"
+}
@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-readme-stats/app/stats"
+	"go-readme-stats/app/svg"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetBadge(t *testing.T) {
+	var captured stats.Options
+	originalFetch := FetchStats
+	FetchStats = func(ignoredLanguagesData []byte, opts stats.Options) ([]stats.Lang, error) {
+		captured = opts
+		return []stats.Lang{
+			{Name: "Go", Percent: 45.5, Colour: "#00ADD8"},
+			{Name: "Java", Percent: 30.2, Colour: "#b07219"},
+			{Name: "Other (2)", Percent: 24.3, Colour: "#F0F6FC", Others: []string{"Shell", "Lua"}},
+		}, nil
+	}
+	defer func() { FetchStats = originalFetch }()
+
+	tests := []struct {
+		name     string
+		url      string
+		expected shieldsResponse
+	}{
+		{"Top language", "/badge", shieldsResponse{1, "top language", "Go 45.5%", "00ADD8", false, cacheMaxAge}},
+		{"Rank", "/badge?rank=2", shieldsResponse{1, "language #2", "Java 30.2%", "b07219", false, cacheMaxAge}},
+		{"Named language", "/badge?lang=java", shieldsResponse{1, "language", "Java 30.2%", "b07219", false, cacheMaxAge}},
+		{"Custom label", "/badge?lang=Go&label=written%20in", shieldsResponse{1, "written in", "Go 45.5%", "00ADD8", false, cacheMaxAge}},
+		{"Other isn't ranked", "/badge?rank=3", shieldsResponse{1, "language #3", "not found", notFoundColor, true, cacheMaxAge}},
+		{"Unused language", "/badge?lang=Rust", shieldsResponse{1, "language", "not found", notFoundColor, true, cacheMaxAge}},
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/badge", GetBadge)
+
+			req, _ := http.NewRequest("GET", tt.url, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+			}
+
+			if w.Header().Get("Cache-Control") != cacheControl {
+				t.Errorf("Expected Cache-Control '%s', got '%s'", cacheControl, w.Header().Get("Cache-Control"))
+			}
+
+			var response shieldsResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Invalid JSON: %v", err)
+			}
+
+			if response != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, response)
+			}
+
+			if captured.LangsCount != stats.MaxLangsCount {
+				t.Errorf("Expected every language to be kept apart, got langs_count %d", captured.LangsCount)
+			}
+		})
+	}
+}
+
+func TestGetBadgeSVG(t *testing.T) {
+	var captured svg.BadgeOptions
+	originalGenerate := GenerateBadge
+	GenerateBadge = func(opts svg.BadgeOptions) (string, error) {
+		captured = opts
+		return "<svg>badge</svg>", nil
+	}
+	defer func() { GenerateBadge = originalGenerate }()
+
+	tests := []struct {
+		name     string
+		url      string
+		expected svg.BadgeOptions
+	}{
+		{"Top language", "/badge.svg", svg.BadgeOptions{Label: "Go", Message: "45.5%", Style: "flat", Theme: "dark"}},
+		{"Named language", "/badge.svg?lang=python&style=for-the-badge&theme=light",
+			svg.BadgeOptions{Label: "Python", Message: "8.5%", Style: "for-the-badge", Theme: "light"}},
+		{"Custom label and colours", "/badge.svg?rank=2&label=second&bg_color=555&text_color=fff",
+			svg.BadgeOptions{Label: "second", Message: "30.2%", Style: "flat", Theme: "dark", Colours: svg.Theme{Background: "555", Text: "fff"}}},
+		{"Unused language", "/badge.svg?lang=Rust", svg.BadgeOptions{Label: "language", Message: "not found", Style: "flat", Theme: "dark"}},
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/badge.svg", GetBadgeSVG)
+
+			req, _ := http.NewRequest("GET", tt.url, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+			}
+
+			if w.Header().Get("Content-Type") != "image/svg+xml" {
+				t.Errorf("Expected Content-Type 'image/svg+xml', got '%s'", w.Header().Get("Content-Type"))
+			}
+
+			if w.Header().Get("Cache-Control") != cacheControl {
+				t.Errorf("Expected Cache-Control '%s', got '%s'", cacheControl, w.Header().Get("Cache-Control"))
+			}
+
+			tt.expected.Colour = captured.Colour // The mock languages have no colours
+			if captured != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, captured)
+			}
+		})
+	}
+}
+
+func TestGetBadge_InvalidOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{"Non-numeric rank", "/badge?rank=first"},
+		{"rank too small", "/badge?rank=0"},
+		{"rank too large", "/badge?rank=21"},
+		{"lang and rank", "/badge?lang=Go&rank=2"},
+		{"Invalid langs_count", "/badge?langs_count=0"},
+		{"Unknown style", "/badge.svg?style=plastic"},
+		{"Invalid bg_color", "/badge.svg?bg_color=notacolour"},
+		{"SVG lang and rank", "/badge.svg?lang=Go&rank=2"},
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/badge", GetBadge)
+			router.GET("/badge.svg", GetBadgeSVG)
+
+			req, _ := http.NewRequest("GET", tt.url, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+		})
+	}
+}
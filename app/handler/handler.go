@@ -2,19 +2,30 @@ package handler
 
 import (
 	_ "embed"
+	"errors"
+	"fmt"
 	"go-readme-stats/app/stats"
 	"go-readme-stats/app/svg"
 	"log"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
 var (
-	FetchStats  = stats.FetchStats
-	GenerateSVG = svg.Generate
+	FetchStats    = stats.FetchStats
+	FetchReport   = stats.FetchReport
+	GenerateSVG   = svg.Generate
+	GeneratePNG   = svg.GeneratePNG
+	GenerateBadge = svg.GenerateBadge
 )
 
+// cacheMaxAge lets clients and CDNs reuse a response for an hour, since the underlying stats change slowly.
+const cacheMaxAge = 3600
+
+var cacheControl = fmt.Sprintf("public, max-age=%d", cacheMaxAge)
+
 //go:embed ignored_languages.json
 var ignoredLanguages []byte
 
@@ -22,19 +33,76 @@ func Favicon(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// GetLanguageStats serves the language card as an SVG, or in the format chosen by the format parameter.
 func GetLanguageStats(c *gin.Context) {
-	theme := c.DefaultQuery("theme", svg.DefaultTheme)
-	header := c.DefaultQuery("header", "Languages")
-	mode := c.DefaultQuery("mode", "bytes")
+	serveLanguageStats(c, c.DefaultQuery("format", "svg"))
+}
+
+// GetLanguageStatsJSON serves the language statistics and their metadata as JSON, like format=json.
+func GetLanguageStatsJSON(c *gin.Context) {
+	serveLanguageStats(c, "json")
+}
 
-	languages, err := FetchStats(ignoredLanguages, mode)
+func serveLanguageStats(c *gin.Context, format string) {
+	opts, err := parseStatsOptions(c)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid parameters: "+err.Error())
+		return
+	}
+
+	svgOpts, err := parseSVGOptions(c)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid parameters: "+err.Error())
+		return
+	}
+
+	switch format {
+	case "svg", "png":
+		// Primary mode only counts repositories, so there are no byte counts to show or to estimate lines from
+		if opts.Mode == "primary" && (svgOpts.Value == "bytes" || svgOpts.Value == "lines") {
+			c.String(http.StatusBadRequest, fmt.Sprintf("Invalid parameters: value=%s needs byte counts, which mode=primary doesn't fetch", svgOpts.Value))
+			return
+		}
+
+		if format == "png" {
+			if err := svg.CheckPNGText(svgOpts); err != nil {
+				c.String(http.StatusBadRequest, "Invalid parameters: "+err.Error()+", use format=svg")
+				return
+			}
+		}
+	case "json":
+		serveReport(c, opts)
+		return
+	default:
+		c.String(http.StatusBadRequest, fmt.Sprintf("Invalid parameters: format must be svg, png or json, got %q", format))
+		return
+	}
+
+	languages, err := FetchStats(ignoredLanguages, opts)
 	if err != nil {
 		log.Printf("Error: Failed to fetch stats for request %s: %v", c.Request.URL.String(), err)
 		c.String(http.StatusInternalServerError, "Error fetching stats")
 		return
 	}
 
-	svgContent, err := GenerateSVG(theme, header, languages)
+	if format == "png" {
+		pngContent, err := GeneratePNG(svgOpts, languages)
+		if errors.Is(err, svg.ErrUnsupportedText) {
+			c.String(http.StatusBadRequest, "Invalid parameters: "+err.Error()+", use format=svg")
+			return
+		}
+		if err != nil {
+			log.Printf("Error: Failed to generate PNG for request %s: %v", c.Request.URL.String(), err)
+			c.String(http.StatusInternalServerError, "Error generating PNG")
+			return
+		}
+
+		c.Header("Cache-Control", cacheControl)
+		c.Data(http.StatusOK, "image/png", pngContent)
+		return
+	}
+
+	svgContent, err := GenerateSVG(svgOpts, languages)
 	if err != nil {
 		log.Printf("Error: Failed to generate SVG for request %s: %v", c.Request.URL.String(), err)
 		c.String(http.StatusInternalServerError, "Error generating SVG")
@@ -42,5 +110,129 @@ func GetLanguageStats(c *gin.Context) {
 	}
 
 	c.Header("Content-Type", "image/svg+xml")
+	c.Header("Cache-Control", cacheControl)
 	c.String(http.StatusOK, svgContent)
 }
+
+// parseStatsOptions reads the scoring and grouping query parameters, falling back to stats.DefaultOptions.
+func parseStatsOptions(c *gin.Context) (stats.Options, error) {
+	opts := stats.DefaultOptions()
+	opts.Mode = c.DefaultQuery("mode", opts.Mode)
+
+	if err := errors.Join(
+		queryInt(c, "langs_count", &opts.LangsCount),
+		queryBool(c, "hide_other", &opts.HideOther),
+		queryFloat(c, "min_percent", &opts.MinPercent),
+		queryInt(c, "decimals", &opts.Decimals),
+	); err != nil {
+		return opts, err
+	}
+
+	return opts, opts.Validate()
+}
+
+// parseSVGOptions reads the rendering query parameters, falling back to svg.DefaultOptions.
+func parseSVGOptions(c *gin.Context) (svg.Options, error) {
+	opts := svg.DefaultOptions()
+	opts.Layout = c.DefaultQuery("layout", opts.Layout)
+	opts.Theme = c.DefaultQuery("theme", opts.Theme)
+	opts.Locale = c.DefaultQuery("locale", opts.Locale)
+	if header, ok := c.GetQuery("header"); ok {
+		opts.Header = header
+	} else {
+		opts.Header = svg.GetLocale(opts.Locale).Header
+	}
+	opts.Value = c.DefaultQuery("value", opts.Value)
+	opts.Colours = svg.Theme{
+		Background:    c.Query("bg_color"),
+		Border:        c.Query("border_color"),
+		Text:          c.Query("text_color"),
+		SecondaryText: c.Query("secondary_text_color"),
+		Other:         c.Query("other_color"),
+	}
+	opts.Palette = c.Query("palette")
+
+	langColours, err := svg.ParseLangColours(c.Query("colors"))
+	if err != nil {
+		return opts, err
+	}
+	opts.LangColours = langColours
+
+	if c.Query("width") == "auto" {
+		opts.AutoWidth = true
+	} else if err := queryFloat(c, "width", &opts.Width); err != nil {
+		return opts, err
+	}
+
+	if err := errors.Join(
+		queryInt(c, "columns", &opts.Columns),
+		queryFloat(c, "row_spacing", &opts.RowSpacing),
+		queryBool(c, "pie_labels", &opts.PieLabels),
+		queryInt(c, "inline_labels", &opts.InlineLabels),
+		queryFloat(c, "min_contrast", &opts.MinContrast),
+		queryOptionalFloat(c, "bar_height", &opts.BarHeight),
+		queryOptionalFloat(c, "border_radius", &opts.BorderRadius),
+		queryBool(c, "hide_border", &opts.HideBorder),
+		queryFloat(c, "scale", &opts.Scale),
+		queryBool(c, "animate", &opts.Animate),
+		queryBool(c, "icons", &opts.Icons),
+	); err != nil {
+		return opts, err
+	}
+
+	return opts, opts.Validate()
+}
+
+// queryInt parses an optional integer query parameter, leaving dst unchanged when it's absent.
+func queryInt(c *gin.Context, name string, dst *int) error {
+	if value, ok := c.GetQuery(name); ok {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s must be an integer, got %q", name, value)
+		}
+		*dst = parsed
+	}
+
+	return nil
+}
+
+// queryFloat parses an optional number query parameter, leaving dst unchanged when it's absent.
+func queryFloat(c *gin.Context, name string, dst *float64) error {
+	if value, ok := c.GetQuery(name); ok {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%s must be a number, got %q", name, value)
+		}
+		*dst = parsed
+	}
+
+	return nil
+}
+
+// queryOptionalFloat parses an optional number query parameter, leaving dst nil when it's absent.
+func queryOptionalFloat(c *gin.Context, name string, dst **float64) error {
+	if _, ok := c.GetQuery(name); !ok {
+		return nil
+	}
+
+	var parsed float64
+	if err := queryFloat(c, name, &parsed); err != nil {
+		return err
+	}
+	*dst = &parsed
+
+	return nil
+}
+
+// queryBool parses an optional boolean query parameter, leaving dst unchanged when it's absent.
+func queryBool(c *gin.Context, name string, dst *bool) error {
+	if value, ok := c.GetQuery(name); ok {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s must be a boolean, got %q", name, value)
+		}
+		*dst = parsed
+	}
+
+	return nil
+}
@@ -2,11 +2,15 @@ package handler
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
+	"time"
 
 	"go-readme-stats/app/stats"
+	"go-readme-stats/app/svg"
 
 	"github.com/gin-gonic/gin"
 )
@@ -14,7 +18,7 @@ import (
 // Mock implementations for tests
 func init() {
 	// Default success mock
-	FetchStats = func(ignoredLanguagesData []byte, mode string) ([]stats.Lang, error) {
+	FetchStats = func(ignoredLanguagesData []byte, opts stats.Options) ([]stats.Lang, error) {
 		return []stats.Lang{
 			{Name: "Go", Percent: 45.5},
 			{Name: "Java", Percent: 30.2},
@@ -23,9 +27,25 @@ func init() {
 		}, nil
 	}
 
-	GenerateSVG = func(theme, header string, languages []stats.Lang) (string, error) {
+	GenerateSVG = func(opts svg.Options, languages []stats.Lang) (string, error) {
 		return "<svg>mock</svg>", nil
 	}
+
+	GeneratePNG = func(opts svg.Options, languages []stats.Lang) ([]byte, error) {
+		return []byte("png mock"), nil
+	}
+
+	FetchReport = func(ignoredLanguagesData []byte, opts stats.Options) (stats.Report, error) {
+		languages, err := FetchStats(ignoredLanguagesData, opts)
+		return stats.Report{
+			Languages:        languages,
+			RepoCount:        3,
+			Skipped:          []stats.SkippedRepo{{Name: "fork-of-gin", Reason: stats.SkipFork}},
+			SkippedPrivate:   2,
+			IgnoredLanguages: []string{"HTML"},
+			GeneratedAt:      time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		}, err
+	}
 }
 
 func TestGetLanguageStats_Success(t *testing.T) {
@@ -84,7 +104,7 @@ func TestGetLanguageStats_Success(t *testing.T) {
 
 func TestGetLanguageStats_StatsFetchFailure(t *testing.T) {
 	originalFetch := FetchStats
-	FetchStats = func(ignoredLanguagesData []byte, mode string) ([]stats.Lang, error) {
+	FetchStats = func(ignoredLanguagesData []byte, opts stats.Options) ([]stats.Lang, error) {
 		return nil, errors.New("API rate limit exceeded")
 	}
 	defer func() { FetchStats = originalFetch }()
@@ -108,7 +128,7 @@ func TestGetLanguageStats_StatsFetchFailure(t *testing.T) {
 
 func TestGetLanguageStats_SVGFailure(t *testing.T) {
 	originalGenerate := GenerateSVG
-	GenerateSVG = func(theme, header string, languages []stats.Lang) (string, error) {
+	GenerateSVG = func(opts svg.Options, languages []stats.Lang) (string, error) {
 		return "", errors.New("template error")
 	}
 	defer func() { GenerateSVG = originalGenerate }()
@@ -130,10 +150,106 @@ func TestGetLanguageStats_SVGFailure(t *testing.T) {
 	}
 }
 
+func TestGetLanguageStats_PNG(t *testing.T) {
+	var captured svg.Options
+	originalGenerate := GeneratePNG
+	GeneratePNG = func(opts svg.Options, languages []stats.Lang) ([]byte, error) {
+		captured = opts
+		return []byte("png mock"), nil
+	}
+	defer func() { GeneratePNG = originalGenerate }()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/langs", GetLanguageStats)
+
+	req, _ := http.NewRequest("GET", "/langs?format=png&scale=2", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	if w.Header().Get("Content-Type") != "image/png" {
+		t.Errorf("Expected Content-Type 'image/png', got '%s'", w.Header().Get("Content-Type"))
+	}
+
+	if w.Header().Get("Cache-Control") != cacheControl {
+		t.Errorf("Expected Cache-Control '%s', got '%s'", cacheControl, w.Header().Get("Cache-Control"))
+	}
+
+	if body := w.Body.String(); body != "png mock" {
+		t.Errorf("Expected body 'png mock', got '%s'", body)
+	}
+
+	if captured.Scale != 2 {
+		t.Errorf("Expected scale 2, got %g", captured.Scale)
+	}
+}
+
+func TestGetLanguageStats_PNGFailure(t *testing.T) {
+	originalGenerate := GeneratePNG
+	GeneratePNG = func(opts svg.Options, languages []stats.Lang) ([]byte, error) {
+		return nil, errors.New("encoding error")
+	}
+	defer func() { GeneratePNG = originalGenerate }()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/langs", GetLanguageStats)
+
+	req, _ := http.NewRequest("GET", "/langs?format=png", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+
+	if body := w.Body.String(); body != "Error generating PNG" {
+		t.Errorf("Expected error message, got '%s'", body)
+	}
+}
+
+func TestGetLanguageStats_PNGLocaleWithLatinHeader(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/langs", GetLanguageStats)
+
+	req, _ := http.NewRequest("GET", "/langs?format=png&locale=ja&header=Code", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+}
+
+func TestGetLanguageStats_PNGUnsupportedText(t *testing.T) {
+	originalGenerate := GeneratePNG
+	GeneratePNG = func(opts svg.Options, languages []stats.Lang) ([]byte, error) {
+		return nil, fmt.Errorf("%w: no glyph for '語'", svg.ErrUnsupportedText)
+	}
+	defer func() { GeneratePNG = originalGenerate }()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/langs", GetLanguageStats)
+
+	req, _ := http.NewRequest("GET", "/langs?format=png&header=%E8%A8%80%E8%AA%9E", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
 func TestGetLanguageStats_InvalidTheme(t *testing.T) {
 	originalGenerate := GenerateSVG
-	GenerateSVG = func(theme, header string, languages []stats.Lang) (string, error) {
-		if theme == "invalid" {
+	GenerateSVG = func(opts svg.Options, languages []stats.Lang) (string, error) {
+		if opts.Theme == "invalid" {
 			return "", errors.New("invalid theme")
 		}
 		return "<svg>mock</svg>", nil
@@ -155,7 +271,7 @@ func TestGetLanguageStats_InvalidTheme(t *testing.T) {
 
 func TestGetLanguageStats_EmptyLanguages(t *testing.T) {
 	originalFetch := FetchStats
-	FetchStats = func(ignoredLanguagesData []byte, mode string) ([]stats.Lang, error) {
+	FetchStats = func(ignoredLanguagesData []byte, opts stats.Options) ([]stats.Lang, error) {
 		return []stats.Lang{}, nil
 	}
 	defer func() { FetchStats = originalFetch }()
@@ -176,3 +292,167 @@ func TestGetLanguageStats_EmptyLanguages(t *testing.T) {
 		t.Errorf("Expected SVG output even with empty languages")
 	}
 }
+
+func TestGetLanguageStats_Options(t *testing.T) {
+	originalFetch := FetchStats
+	defer func() { FetchStats = originalFetch }()
+
+	var received stats.Options
+	FetchStats = func(ignoredLanguagesData []byte, opts stats.Options) ([]stats.Lang, error) {
+		received = opts
+		return []stats.Lang{}, nil
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/langs", GetLanguageStats)
+
+	req, _ := http.NewRequest("GET", "/langs?mode=stars&langs_count=10&hide_other=true&min_percent=2.5&decimals=2", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	expected := stats.Options{Mode: "stars", LangsCount: 10, HideOther: true, MinPercent: 2.5, Decimals: 2}
+	if received != expected {
+		t.Errorf("Expected options %+v, got %+v", expected, received)
+	}
+}
+
+func TestGetLanguageStats_SVGOptions(t *testing.T) {
+	originalGenerate := GenerateSVG
+	defer func() { GenerateSVG = originalGenerate }()
+
+	var received svg.Options
+	GenerateSVG = func(opts svg.Options, languages []stats.Lang) (string, error) {
+		received = opts
+		return "<svg>mock</svg>", nil
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/langs", GetLanguageStats)
+
+	req, _ := http.NewRequest("GET", "/langs?layout=pie&theme=light&header=Code&value=lines&columns=3&row_spacing=24&pie_labels=true&inline_labels=2&bg_color=fff&other_color=%23808080&palette=tol&colors=Go:00ADD8,C%2B%2B:red&min_contrast=3&width=auto&bar_height=12&border_radius=0&hide_border=true&scale=2&animate=true&icons=true", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	barHeight, borderRadius := 12.0, 0.0
+	expected := svg.Options{Layout: "pie", Theme: "light", Header: "Code", Value: "lines", Columns: 3, RowSpacing: 24, PieLabels: true, InlineLabels: 2,
+		Colours: svg.Theme{Background: "fff", Other: "#808080"}, Palette: "tol", LangColours: map[string]string{"Go": "00ADD8", "C++": "red"}, MinContrast: 3, AutoWidth: true, Locale: "en",
+		Width: svg.DefaultWidth, BarHeight: &barHeight, BorderRadius: &borderRadius, HideBorder: true, Scale: 2, Animate: true, Icons: true}
+	if !reflect.DeepEqual(received, expected) {
+		t.Errorf("Expected options %+v, got %+v", expected, received)
+	}
+}
+
+func TestGetLanguageStats_LocaleHeader(t *testing.T) {
+	originalGenerate := GenerateSVG
+	defer func() { GenerateSVG = originalGenerate }()
+
+	tests := []struct {
+		name     string
+		url      string
+		expected string
+	}{
+		{"Default", "/langs", "Languages"},
+		{"Localised default", "/langs?locale=de", "Sprachen"},
+		{"Regional tag", "/langs?locale=fr-CA", "Langages"},
+		{"Explicit header", "/langs?locale=de&header=Code", "Code"},
+		{"Hidden header", "/langs?locale=de&header=", ""},
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received svg.Options
+			GenerateSVG = func(opts svg.Options, languages []stats.Lang) (string, error) {
+				received = opts
+				return "<svg>mock</svg>", nil
+			}
+
+			router := gin.New()
+			router.GET("/langs", GetLanguageStats)
+
+			req, _ := http.NewRequest("GET", tt.url, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+			}
+			if received.Header != tt.expected {
+				t.Errorf("Expected header %q, got %q", tt.expected, received.Header)
+			}
+		})
+	}
+}
+
+func TestGetLanguageStats_InvalidOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{"Unknown mode", "/langs?mode=primry"},
+		{"Primary mode with bytes", "/langs?mode=primary&value=bytes"},
+		{"Primary mode with lines", "/langs?mode=primary&value=lines"},
+		{"Non-numeric langs_count", "/langs?langs_count=many"},
+		{"langs_count too small", "/langs?langs_count=0"},
+		{"langs_count leaves no room beside Other", "/langs?langs_count=1"},
+		{"langs_count too large", "/langs?langs_count=21"},
+		{"Invalid hide_other", "/langs?hide_other=maybe"},
+		{"Non-numeric min_percent", "/langs?min_percent=abc"},
+		{"min_percent out of range", "/langs?min_percent=150"},
+		{"decimals out of range", "/langs?decimals=3"},
+		{"Unknown layout", "/langs?layout=radar"},
+		{"Unknown value", "/langs?value=words"},
+		{"Too many columns", "/langs?columns=4"},
+		{"Non-numeric row_spacing", "/langs?row_spacing=wide"},
+		{"Invalid bg_color", "/langs?bg_color=notacolour"},
+		{"Injected text_color", "/langs?text_color=red%3B%7D%3C/style%3E"},
+		{"Unknown palette", "/langs?palette=rainbow"},
+		{"min_contrast out of range", "/langs?min_contrast=30"},
+		{"Unknown width", "/langs?width=wide"},
+		{"width too small", "/langs?width=100"},
+		{"width too large", "/langs?width=2000"},
+		{"bar_height out of range", "/langs?bar_height=40"},
+		{"Non-numeric border_radius", "/langs?border_radius=round"},
+		{"Negative border_radius", "/langs?border_radius=-1"},
+		{"Invalid hide_border", "/langs?hide_border=sometimes"},
+		{"scale too large", "/langs?scale=10"},
+		{"Invalid animate", "/langs?animate=yes"},
+		{"Invalid icons", "/langs?icons=please"},
+		{"Unknown locale", "/langs?locale=xx"},
+		{"Malformed colors", "/langs?colors=Go"},
+		{"Invalid language colour", "/langs?colors=Go:notacolour"},
+		{"Unknown format", "/langs?format=gif"},
+		{"PNG in Japanese", "/langs?format=png&locale=ja"},
+		{"PNG in Chinese", "/langs?format=png&locale=zh"},
+		{"PNG in Arabic", "/langs?format=png&locale=ar"},
+		{"PNG in Hebrew", "/langs?format=png&locale=he"},
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/langs", GetLanguageStats)
+
+			req, _ := http.NewRequest("GET", tt.url, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"go-readme-stats/app/stats"
+
+	"github.com/gin-gonic/gin"
+)
+
+// schemaVersion is bumped whenever a field of the JSON response is renamed, removed or changes meaning.
+// Adding fields keeps the version.
+const schemaVersion = 1
+
+type statsResponse struct {
+	SchemaVersion  int                   `json:"schema_version"`
+	GeneratedAt    time.Time             `json:"generated_at"`
+	Mode           string                `json:"mode"`
+	RepoCount      int                   `json:"repo_count"`
+	Filters        filtersResponse       `json:"filters"`
+	SkippedRepos   []skippedRepoResponse `json:"skipped_repos"`
+	SkippedPrivate int                   `json:"skipped_private_repos"`
+	Languages      []langResponse        `json:"languages"`
+}
+
+type filtersResponse struct {
+	LangsCount       int      `json:"langs_count"`
+	HideOther        bool     `json:"hide_other"`
+	MinPercent       float64  `json:"min_percent"`
+	Decimals         int      `json:"decimals"`
+	IgnoredLanguages []string `json:"ignored_languages"`
+}
+
+type skippedRepoResponse struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+type langResponse struct {
+	Name      string   `json:"name"`
+	Percent   float64  `json:"percent"`
+	Color     string   `json:"color"`
+	Bytes     int      `json:"bytes"`
+	Lines     int      `json:"lines"`
+	RepoCount int      `json:"repo_count"`
+	Score     float64  `json:"score"`
+	Others    []string `json:"others,omitempty"`
+}
+
+// serveReport fetches the statistics with their metadata and serves them as JSON.
+func serveReport(c *gin.Context, opts stats.Options) {
+	report, err := FetchReport(ignoredLanguages, opts)
+	if err != nil {
+		log.Printf("Error: Failed to fetch stats for request %s: %v", c.Request.URL.String(), err)
+		c.String(http.StatusInternalServerError, "Error fetching stats")
+		return
+	}
+
+	c.Header("Cache-Control", cacheControl)
+	c.JSON(http.StatusOK, newStatsResponse(report, opts))
+}
+
+// newStatsResponse converts a report to the versioned JSON schema, with empty lists rather than nulls.
+func newStatsResponse(report stats.Report, opts stats.Options) statsResponse {
+	response := statsResponse{
+		SchemaVersion: schemaVersion,
+		GeneratedAt:   report.GeneratedAt,
+		Mode:          opts.Mode,
+		RepoCount:     report.RepoCount,
+		Filters: filtersResponse{
+			LangsCount:       opts.LangsCount,
+			HideOther:        opts.HideOther,
+			MinPercent:       opts.MinPercent,
+			Decimals:         opts.Decimals,
+			IgnoredLanguages: append([]string{}, report.IgnoredLanguages...),
+		},
+		SkippedRepos:   make([]skippedRepoResponse, 0, len(report.Skipped)),
+		SkippedPrivate: report.SkippedPrivate,
+		Languages:      make([]langResponse, 0, len(report.Languages)),
+	}
+
+	for _, repo := range report.Skipped {
+		response.SkippedRepos = append(response.SkippedRepos, skippedRepoResponse(repo))
+	}
+
+	for _, lang := range report.Languages {
+		response.Languages = append(response.Languages, langResponse{
+			Name:      lang.Name,
+			Percent:   lang.Percent,
+			Color:     lang.Colour,
+			Bytes:     lang.Bytes,
+			Lines:     lang.Lines,
+			RepoCount: lang.RepoCount,
+			Score:     lang.Score,
+			Others:    lang.Others,
+		})
+	}
+
+	return response
+}
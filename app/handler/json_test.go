@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-readme-stats/app/stats"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetLanguageStats_JSON(t *testing.T) {
+	urls := []string{"/langs.json?langs_count=4&hide_other=true", "/langs?format=json&langs_count=4&hide_other=true"}
+
+	gin.SetMode(gin.TestMode)
+
+	for _, url := range urls {
+		t.Run(url, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/langs", GetLanguageStats)
+			router.GET("/langs.json", GetLanguageStatsJSON)
+
+			req, _ := http.NewRequest("GET", url, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+			}
+
+			if w.Header().Get("Content-Type") != "application/json; charset=utf-8" {
+				t.Errorf("Expected JSON Content-Type, got '%s'", w.Header().Get("Content-Type"))
+			}
+
+			if w.Header().Get("Cache-Control") != cacheControl {
+				t.Errorf("Expected Cache-Control '%s', got '%s'", cacheControl, w.Header().Get("Cache-Control"))
+			}
+
+			var response statsResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Invalid JSON: %v", err)
+			}
+
+			if response.SchemaVersion != schemaVersion || response.Mode != "bytes" || response.RepoCount != 3 {
+				t.Errorf("Unexpected metadata: %+v", response)
+			}
+
+			if !response.GeneratedAt.Equal(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)) {
+				t.Errorf("Expected generation time from the report, got %v", response.GeneratedAt)
+			}
+
+			if response.Filters.LangsCount != 4 || !response.Filters.HideOther || response.Filters.Decimals != stats.DefaultDecimals {
+				t.Errorf("Filters don't match the query: %+v", response.Filters)
+			}
+
+			if len(response.SkippedRepos) != 1 || response.SkippedRepos[0] != (skippedRepoResponse{"fork-of-gin", stats.SkipFork}) {
+				t.Errorf("Unexpected skipped repos: %+v", response.SkippedRepos)
+			}
+
+			if response.SkippedPrivate != 2 {
+				t.Errorf("Expected 2 skipped private repos, got %d", response.SkippedPrivate)
+			}
+
+			if len(response.Languages) != 4 || response.Languages[0].Name != "Go" || response.Languages[0].Percent != 45.5 {
+				t.Errorf("Unexpected languages: %+v", response.Languages)
+			}
+		})
+	}
+}
+
+func TestGetLanguageStats_JSONEmptyLists(t *testing.T) {
+	originalFetch := FetchReport
+	FetchReport = func(ignoredLanguagesData []byte, opts stats.Options) (stats.Report, error) {
+		return stats.Report{}, nil
+	}
+	defer func() { FetchReport = originalFetch }()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/langs.json", GetLanguageStatsJSON)
+
+	req, _ := http.NewRequest("GET", "/langs.json", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var raw map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &raw); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+
+	for _, field := range []string{"skipped_repos", "languages"} {
+		if _, ok := raw[field].([]any); !ok {
+			t.Errorf("Expected %s to be an empty list, got %v", field, raw[field])
+		}
+	}
+}
+
+func TestGetLanguageStats_JSONFailure(t *testing.T) {
+	originalFetch := FetchReport
+	FetchReport = func(ignoredLanguagesData []byte, opts stats.Options) (stats.Report, error) {
+		return stats.Report{}, errors.New("API rate limit exceeded")
+	}
+	defer func() { FetchReport = originalFetch }()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/langs.json", GetLanguageStatsJSON)
+
+	req, _ := http.NewRequest("GET", "/langs.json", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+
+	if body := w.Body.String(); body != "Error fetching stats" {
+		t.Errorf("Expected error message, got '%s'", body)
+	}
+}
+
+func TestGetLanguageStats_JSONInvalidOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{"langs_count too small", "/langs.json?langs_count=0"},
+		{"Unknown mode", "/langs.json?mode=foo"},
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/langs.json", GetLanguageStatsJSON)
+
+			req, _ := http.NewRequest("GET", tt.url, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+		})
+	}
+}
@@ -17,6 +17,9 @@ func Register(app *gin.Engine) {
 	route := app.Group("/api")
 	{
 		route.GET("/langs", handler.GetLanguageStats)
+		route.GET("/langs.json", handler.GetLanguageStatsJSON)
+		route.GET("/badge", handler.GetBadge)
+		route.GET("/badge.svg", handler.GetBadgeSVG)
 	}
 }
 
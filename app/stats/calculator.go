@@ -7,27 +7,31 @@ import (
 )
 
 const (
-	maxVisibleLanguages = 6
-	topLanguagesCount   = maxVisibleLanguages - 1 // Number of individual languages before grouping into "Other"
-	percentPrecision    = 10                      // One decimal precision (e.g. 10.4%)
+	starBaseline = 1 // Added to each repo's stars so unstarred repos still count
 )
 
-// calculateStats computes language percentages by raw bytes or geometric mean.
-// Languages are sorted by descending percentage, then ascending name.
-// If more than maxVisibleLanguages exist, languages beyond topLanguagesCount are grouped into "Other".
-func calculateStats(languageTotals, languageFreq map[string]int, mode string) []Lang {
+// calculateStats computes language percentages by raw bytes, geometric mean, star weighting or primary language count.
+// Languages are sorted by descending percentage, then ascending name, and grouped by groupLanguages.
+func calculateStats(usage map[string]languageUsage, opts Options) []Lang {
 	scores := make(map[string]float64)
 	var totalScore float64
 
-	for lang, bytes := range languageTotals {
-		freq := languageFreq[lang]
+	for lang, u := range usage {
 		var score float64
 
-		switch mode {
+		switch opts.Mode {
 		case "geometric": // Geometric mean: sqrt(bytes * freq)
-			score = math.Sqrt(float64(bytes) * float64(freq))
+			score = math.Sqrt(float64(u.Bytes) * float64(len(u.Repos)))
+		case "stars": // Byte share of each repo weighted by (stars + starBaseline)
+			score = u.Stars
+		case "primary": // Number of repos with this primary language
+			score = float64(u.Primary)
 		default: // Raw byte count
-			score = float64(bytes)
+			score = float64(u.Bytes)
+		}
+
+		if score <= 0 {
+			continue
 		}
 
 		scores[lang] = score
@@ -37,8 +41,12 @@ func calculateStats(languageTotals, languageFreq map[string]int, mode string) []
 	var result []Lang
 	for lang, score := range scores {
 		result = append(result, Lang{
-			Name:    lang,
-			Percent: score / totalScore * 100,
+			Name:      lang,
+			Percent:   score / totalScore * 100,
+			Bytes:     usage[lang].Bytes,
+			Lines:     usage[lang].Lines,
+			RepoCount: len(usage[lang].Repos),
+			Score:     score,
 		})
 	}
 
@@ -51,26 +59,152 @@ func calculateStats(languageTotals, languageFreq map[string]int, mode string) []
 		return result[i].Percent > result[j].Percent
 	})
 
-	// Combine languages below topLanguagesCount into "Other"
-	if len(result) > maxVisibleLanguages {
-		var otherPercent float64
-		for _, lang := range result[topLanguagesCount:] {
-			otherPercent += lang.Percent
+	result = groupLanguages(result, opts)
+	roundPercents(result, opts.Decimals)
+
+	for i := range result {
+		if result[i].IsOther() {
+			result[i].RepoCount = countRepos(usage, result[i].Others)
 		}
+	}
 
-		result = append(result[:topLanguagesCount], Lang{
-			Name:    fmt.Sprintf("Other (%d)", len(result)-topLanguagesCount),
-			Percent: otherPercent,
-		})
+	return result
+}
+
+// groupLanguages limits the result to opts.LangsCount entries.
+// Languages below opts.MinPercent, and those beyond the limit, are folded into "Other",
+// or dropped with the remaining languages renormalised to 100% when opts.HideOther is set.
+// A single folded language keeps its own entry instead of becoming "Other (1)".
+func groupLanguages(languages []Lang, opts Options) []Lang {
+	limit := opts.LangsCount
+	if limit <= 0 {
+		limit = DefaultLangsCount
 	}
 
-	for i := range result {
-		result[i].Percent = roundPercent(result[i].Percent)
+	var shown, folded []Lang
+	for _, lang := range languages {
+		if lang.Percent < opts.MinPercent {
+			folded = append(folded, lang)
+		} else {
+			shown = append(shown, lang)
+		}
 	}
 
-	return result
+	if opts.HideOther {
+		if len(shown) > limit {
+			shown = shown[:limit]
+		}
+		return renormalise(shown)
+	}
+
+	// Reserve the last slot for "Other" when everything doesn't fit
+	if len(shown)+min(len(folded), 1) > limit {
+		cut := limit - 1
+		folded = append(append([]Lang{}, shown[cut:]...), folded...)
+		shown = shown[:cut]
+	}
+
+	// The limit always folds at least two languages, so a lone one is below MinPercent and still fits
+	switch len(folded) {
+	case 0:
+		return shown
+	case 1:
+		return append(shown, folded[0])
+	}
+
+	other := Lang{Name: fmt.Sprintf("Other (%d)", len(folded))}
+	for _, lang := range folded {
+		other.Percent += lang.Percent
+		other.Bytes += lang.Bytes
+		other.Lines += lang.Lines
+		other.Score += lang.Score
+		other.Others = append(other.Others, lang.Name)
+	}
+
+	return append(shown, other)
+}
+
+// countRepos returns the number of distinct repositories using any of the given languages.
+func countRepos(usage map[string]languageUsage, languages []string) int {
+	repos := make(map[string]struct{})
+	for _, lang := range languages {
+		for _, repo := range usage[lang].Repos {
+			repos[repo] = struct{}{}
+		}
+	}
+
+	return len(repos)
 }
 
-func roundPercent(percent float64) float64 {
-	return math.Round(percent*percentPrecision) / percentPrecision
+// renormalise scales percentages so the given languages sum to 100%.
+func renormalise(languages []Lang) []Lang {
+	var total float64
+	for _, lang := range languages {
+		total += lang.Percent
+	}
+
+	for i := range languages {
+		languages[i].Percent = languages[i].Percent / total * 100
+	}
+
+	return languages
+}
+
+// roundPercents rounds percentages to the given number of decimals using the largest remainder (Hamilton) method,
+// so the rounded values always sum to exactly 100. Entries that would round to zero are floored to the smallest
+// displayable unit, taken from the largest entry, so every language with a bar also has a visible percentage.
+func roundPercents(languages []Lang, decimals int) {
+	if len(languages) == 0 {
+		return
+	}
+
+	scale := math.Pow10(decimals)
+	total := int(math.Round(100 * scale))
+
+	units := make([]int, len(languages))
+	remainders := make([]float64, len(languages))
+	assigned := 0
+
+	for i, lang := range languages {
+		exact := lang.Percent * scale
+		units[i] = int(math.Floor(exact))
+		remainders[i] = exact - float64(units[i])
+		assigned += units[i]
+	}
+
+	// Hand out the remaining units by largest remainder, ties going to the higher ranked language
+	order := make([]int, len(languages))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]] > remainders[order[b]]
+	})
+
+	for i := 0; assigned < total; i++ {
+		units[order[i%len(order)]]++
+		assigned++
+	}
+
+	for i := range units {
+		if units[i] > 0 {
+			continue
+		}
+
+		largest := 0
+		for j := range units {
+			if units[j] > units[largest] {
+				largest = j
+			}
+		}
+
+		if units[largest] > 1 {
+			units[largest]--
+			units[i]++
+		}
+	}
+
+	for i := range languages {
+		languages[i].Percent = float64(units[i]) / scale
+	}
 }
@@ -1,9 +1,25 @@
 package stats
 
 import (
+	"fmt"
+	"math"
 	"testing"
+	"testing/quick"
 )
 
+// usageFrom builds language usage from byte totals and repository counts.
+func usageFrom(totals, freq map[string]int) map[string]languageUsage {
+	usage := make(map[string]languageUsage, len(totals))
+	for lang, bytes := range totals {
+		u := languageUsage{Bytes: bytes}
+		for i := range freq[lang] {
+			u.Repos = append(u.Repos, fmt.Sprintf("repo%d", i))
+		}
+		usage[lang] = u
+	}
+	return usage
+}
+
 func TestCalculateStats_Raw(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -50,7 +66,7 @@ func TestCalculateStats_Raw(t *testing.T) {
 				"HTML":       100,
 			},
 			expected: []Lang{
-				{Name: "Go", Percent: 45.5},
+				{Name: "Go", Percent: 45.4},
 				{Name: "JavaScript", Percent: 18.2},
 				{Name: "Python", Percent: 13.6},
 				{Name: "Java", Percent: 9.1},
@@ -68,7 +84,7 @@ func TestCalculateStats_Raw(t *testing.T) {
 				freq[lang] = 1
 			}
 
-			result := calculateStats(tt.input, freq, "")
+			result := calculateStats(usageFrom(tt.input, freq), DefaultOptions())
 
 			if len(result) != len(tt.expected) {
 				t.Errorf("got %d languages, expected %d", len(result), len(tt.expected))
@@ -131,7 +147,7 @@ func TestCalculateStats_Geometric(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := calculateStats(tt.totals, tt.freq, "geometric")
+			result := calculateStats(usageFrom(tt.totals, tt.freq), Options{Mode: "geometric", LangsCount: DefaultLangsCount, Decimals: DefaultDecimals})
 
 			if len(result) != len(tt.expected) {
 				t.Errorf("got %d languages, expected %d", len(result), len(tt.expected))
@@ -150,24 +166,246 @@ func TestCalculateStats_Geometric(t *testing.T) {
 	}
 }
 
-func TestRoundPercent(t *testing.T) {
+func TestCalculateStats_Stars(t *testing.T) {
+	usage := map[string]languageUsage{
+		"Go":     {Bytes: 1000, Repos: []string{"api"}, Stars: 30},
+		"Python": {Bytes: 9000, Repos: []string{"ml", "scripts"}, Stars: 10},
+		"Rust":   {Bytes: 500, Repos: []string{"cli"}, Stars: 0},
+	}
+
+	expected := []Lang{
+		{Name: "Go", Percent: 75.0},
+		{Name: "Python", Percent: 25.0},
+	}
+
+	result := calculateStats(usage, Options{Mode: "stars", LangsCount: DefaultLangsCount, Decimals: DefaultDecimals})
+	if len(result) != len(expected) {
+		t.Fatalf("got %d languages, expected %d", len(result), len(expected))
+	}
+
+	for i, lang := range result {
+		if lang.Name != expected[i].Name || lang.Percent != expected[i].Percent {
+			t.Errorf("[%d] = %s %v, expected %s %v", i, lang.Name, lang.Percent, expected[i].Name, expected[i].Percent)
+		}
+	}
+}
+
+func TestCalculateStats_Primary(t *testing.T) {
+	usage := map[string]languageUsage{
+		"Go":         {Repos: []string{"api", "cli", "bot"}, Primary: 3},
+		"TypeScript": {Repos: []string{"web"}, Primary: 1},
+	}
+
+	expected := []Lang{
+		{Name: "Go", Percent: 75.0},
+		{Name: "TypeScript", Percent: 25.0},
+	}
+
+	result := calculateStats(usage, Options{Mode: "primary", LangsCount: DefaultLangsCount, Decimals: DefaultDecimals})
+	if len(result) != len(expected) {
+		t.Fatalf("got %d languages, expected %d", len(result), len(expected))
+	}
+
+	for i, lang := range result {
+		if lang.Name != expected[i].Name || lang.Percent != expected[i].Percent {
+			t.Errorf("[%d] = %s %v, expected %s %v", i, lang.Name, lang.Percent, expected[i].Name, expected[i].Percent)
+		}
+	}
+}
+
+func TestCalculateStats_Metrics(t *testing.T) {
+	usage := map[string]languageUsage{
+		"Go":     {Bytes: 6000, Repos: []string{"api", "cli"}},
+		"Python": {Bytes: 2000, Repos: []string{"ml"}},
+		"Shell":  {Bytes: 1000, Repos: []string{"api", "ml"}},
+		"Lua":    {Bytes: 1000, Repos: []string{"nvim"}},
+	}
+
+	result := calculateStats(usage, Options{LangsCount: 3, Decimals: DefaultDecimals})
+	if len(result) != 3 {
+		t.Fatalf("got %d languages, expected 3", len(result))
+	}
+
+	if goLang := result[0]; goLang.Bytes != 6000 || goLang.RepoCount != 2 || goLang.Score != 6000 {
+		t.Errorf("Go = %+v, want 6000 bytes across 2 repos with score 6000", goLang)
+	}
+
+	other := result[2]
+	if !other.IsOther() {
+		t.Fatalf("last entry %s should be Other", other.Name)
+	}
+
+	if other.Bytes != 2000 || other.Score != 2000 {
+		t.Errorf("Other bytes = %d, score = %v, want 2000 for both", other.Bytes, other.Score)
+	}
+
+	// Shell and Lua share no repositories, so api, ml and nvim are counted once each
+	if other.RepoCount != 3 {
+		t.Errorf("Other repo count = %d, want 3", other.RepoCount)
+	}
+
+	expectedOthers := []string{"Lua", "Shell"}
+	if len(other.Others) != len(expectedOthers) || other.Others[0] != expectedOthers[0] || other.Others[1] != expectedOthers[1] {
+		t.Errorf("Other languages = %v, want %v", other.Others, expectedOthers)
+	}
+}
+
+func TestGroupLanguages(t *testing.T) {
+	languages := []Lang{
+		{Name: "Go", Percent: 40},
+		{Name: "Python", Percent: 30},
+		{Name: "Rust", Percent: 20},
+		{Name: "C", Percent: 6},
+		{Name: "Lua", Percent: 4},
+	}
+
 	tests := []struct {
 		name     string
-		input    float64
-		expected float64
+		opts     Options
+		expected []Lang
 	}{
-		{"Round down", 10.14, 10.1},
-		{"Round up", 10.16, 10.2},
-		{"Exact", 10.1, 10.1},
-		{"Zero", 0.0, 0.0},
+		{
+			name:     "All languages fit",
+			opts:     Options{LangsCount: 5},
+			expected: languages,
+		},
+		{
+			name: "Limit folds the tail into Other",
+			opts: Options{LangsCount: 3},
+			expected: []Lang{
+				{Name: "Go", Percent: 40},
+				{Name: "Python", Percent: 30},
+				{Name: "Other (3)", Percent: 30},
+			},
+		},
+		{
+			name: "Minimum percent folds small languages",
+			opts: Options{LangsCount: 20, MinPercent: 10},
+			expected: []Lang{
+				{Name: "Go", Percent: 40},
+				{Name: "Python", Percent: 30},
+				{Name: "Rust", Percent: 20},
+				{Name: "Other (2)", Percent: 10},
+			},
+		},
+		{
+			name:     "Lone language below minimum keeps its name",
+			opts:     Options{LangsCount: 20, MinPercent: 5},
+			expected: languages,
+		},
+		{
+			name: "Minimum percent and limit combined",
+			opts: Options{LangsCount: 3, MinPercent: 10},
+			expected: []Lang{
+				{Name: "Go", Percent: 40},
+				{Name: "Python", Percent: 30},
+				{Name: "Other (3)", Percent: 30},
+			},
+		},
+		{
+			name: "Hide other renormalises",
+			opts: Options{LangsCount: 2, HideOther: true},
+			expected: []Lang{
+				{Name: "Go", Percent: 40.0 / 70 * 100},
+				{Name: "Python", Percent: 30.0 / 70 * 100},
+			},
+		},
+		{
+			name: "Hide other drops languages below minimum",
+			opts: Options{LangsCount: 20, HideOther: true, MinPercent: 25},
+			expected: []Lang{
+				{Name: "Go", Percent: 40.0 / 70 * 100},
+				{Name: "Python", Percent: 30.0 / 70 * 100},
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := roundPercent(tt.input)
-			if result != tt.expected {
-				t.Errorf("roundPercent(%v) = %v, expected %v", tt.input, result, tt.expected)
+			input := append([]Lang{}, languages...)
+			result := groupLanguages(input, tt.opts)
+
+			if len(result) != len(tt.expected) {
+				t.Fatalf("got %d languages, expected %d", len(result), len(tt.expected))
+			}
+
+			for i, lang := range result {
+				if lang.Name != tt.expected[i].Name || math.Abs(lang.Percent-tt.expected[i].Percent) > 1e-9 {
+					t.Errorf("[%d] = %s %v, expected %s %v", i, lang.Name, lang.Percent, tt.expected[i].Name, tt.expected[i].Percent)
+				}
 			}
 		})
 	}
 }
+
+func TestRoundPercents(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []float64
+		decimals int
+		expected []float64
+	}{
+		{"Remainders decide rounding direction", []float64{45.4545, 18.1818, 13.6364, 9.0909, 7.2727, 6.3637}, 1, []float64{45.4, 18.2, 13.6, 9.1, 7.3, 6.4}},
+		{"Thirds at whole percent", []float64{100.0 / 3, 100.0 / 3, 100.0 / 3}, 0, []float64{34, 33, 33}},
+		{"Two decimals", []float64{100.0 / 3, 100.0 / 3, 100.0 / 3}, 2, []float64{33.34, 33.33, 33.33}},
+		{"Exact values unchanged", []float64{50, 30, 20}, 1, []float64{50, 30, 20}},
+		{"Tiny language floored to minimum", []float64{99.98, 0.02}, 1, []float64{99.9, 0.1}},
+		{"Tiny language at whole percent", []float64{99.6, 0.4}, 0, []float64{99, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			languages := make([]Lang, len(tt.input))
+			for i, percent := range tt.input {
+				languages[i].Percent = percent
+			}
+
+			roundPercents(languages, tt.decimals)
+
+			for i, lang := range languages {
+				if lang.Percent != tt.expected[i] {
+					t.Errorf("[%d] = %v, expected %v", i, lang.Percent, tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+// TestCalculateStats_SumInvariant checks that rounded percentages always sum to exactly 100
+// and no language rounds to zero, for arbitrary byte distributions and options.
+func TestCalculateStats_SumInvariant(t *testing.T) {
+	property := func(sizes []uint32, langsCount, decimals uint8, hideOther bool) bool {
+		usage := make(map[string]languageUsage)
+		for i, size := range sizes {
+			usage[fmt.Sprintf("Lang%d", i)] = languageUsage{Bytes: int(size%1_000_000) + 1, Repos: []string{"repo"}}
+		}
+
+		opts := Options{
+			Mode:       "bytes",
+			LangsCount: int(langsCount)%MaxLangsCount + 1,
+			HideOther:  hideOther,
+			Decimals:   int(decimals) % (MaxDecimals + 1),
+		}
+
+		result := calculateStats(usage, opts)
+		if len(result) == 0 {
+			return len(sizes) == 0
+		}
+
+		scale := math.Pow10(opts.Decimals)
+		units := 0
+		for _, lang := range result {
+			unit := int(math.Round(lang.Percent * scale))
+			if unit <= 0 {
+				return false
+			}
+			units += unit
+		}
+
+		return units == int(100*scale)
+	}
+
+	if err := quick.Check(property, &quick.Config{MaxCount: 1000}); err != nil {
+		t.Error(err)
+	}
+}
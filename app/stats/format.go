@@ -0,0 +1,54 @@
+package stats
+
+import (
+	"fmt"
+	"strconv"
+)
+
+var byteUnits = []string{"B", "KB", "MB", "GB", "TB"}
+
+// FormatBytes returns a human-readable byte size using binary multiples (e.g. 1.2 MB).
+// Whole bytes are shown without decimals, larger units with one decimal place.
+func FormatBytes(bytes int) string {
+	if bytes < 1024 {
+		return strconv.Itoa(bytes) + " B"
+	}
+
+	size := float64(bytes)
+	unit := 0
+	for size >= 1024 && unit < len(byteUnits)-1 {
+		size /= 1024
+		unit++
+	}
+
+	// Avoid "1024.0 KB" when rounding pushes the value into the next unit
+	if size >= 1023.95 && unit < len(byteUnits)-1 {
+		size /= 1024
+		unit++
+	}
+
+	return fmt.Sprintf("%.1f %s", size, byteUnits[unit])
+}
+
+// FormatRepos returns the repository count with a singular or plural noun (e.g. 14 repos).
+func FormatRepos(count int) string {
+	if count == 1 {
+		return "1 repo"
+	}
+
+	return strconv.Itoa(count) + " repos"
+}
+
+// FormatLines returns an approximate line count with a metric suffix (e.g. ≈12.4k lines).
+func FormatLines(lines int) string {
+	switch {
+	case lines >= 999_950:
+		return fmt.Sprintf("≈%.1fM lines", float64(lines)/1_000_000)
+	case lines >= 1000:
+		return fmt.Sprintf("≈%.1fk lines", float64(lines)/1000)
+	case lines == 1:
+		return "≈1 line"
+	default:
+		return fmt.Sprintf("≈%d lines", lines)
+	}
+}
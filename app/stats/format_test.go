@@ -0,0 +1,62 @@
+package stats
+
+import "testing"
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		bytes    int
+		expected string
+	}{
+		{0, "0 B"},
+		{512, "512 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KB"},
+		{1536, "1.5 KB"},
+		{1_258_291, "1.2 MB"},
+		{1_048_575, "1.0 MB"},
+		{5 * 1024 * 1024 * 1024, "5.0 GB"},
+	}
+
+	for _, tt := range tests {
+		if result := FormatBytes(tt.bytes); result != tt.expected {
+			t.Errorf("FormatBytes(%d) = %s, want %s", tt.bytes, result, tt.expected)
+		}
+	}
+}
+
+func TestFormatRepos(t *testing.T) {
+	tests := []struct {
+		count    int
+		expected string
+	}{
+		{0, "0 repos"},
+		{1, "1 repo"},
+		{14, "14 repos"},
+	}
+
+	for _, tt := range tests {
+		if result := FormatRepos(tt.count); result != tt.expected {
+			t.Errorf("FormatRepos(%d) = %s, want %s", tt.count, result, tt.expected)
+		}
+	}
+}
+
+func TestFormatLines(t *testing.T) {
+	tests := []struct {
+		lines    int
+		expected string
+	}{
+		{0, "≈0 lines"},
+		{1, "≈1 line"},
+		{999, "≈999 lines"},
+		{12_400, "≈12.4k lines"},
+		{999_960, "≈1.0M lines"},
+		{2_500_000, "≈2.5M lines"},
+	}
+
+	for _, tt := range tests {
+		if result := FormatLines(tt.lines); result != tt.expected {
+			t.Errorf("FormatLines(%d) = %s, want %s", tt.lines, result, tt.expected)
+		}
+	}
+}
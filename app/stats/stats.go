@@ -5,73 +5,275 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"math"
+	"sort"
+	"time"
 )
 
 //go:embed colours.json
 var coloursJSON []byte
 
+//go:embed bytes_per_line.json
+var bytesPerLineJSON []byte
+
 const (
-	defaultColour = "#F0F6FC"
+	defaultColour       = "#F0F6FC"
+	defaultBytesPerLine = 32 // Used for languages without their own factor
+
+	DefaultLangsCount      = 6 // Five languages plus "Other"
+	MinLangsCount          = 1
+	MinLangsCountWithOther = 2 // Leaves room for a named language beside "Other"
+	MaxLangsCount          = 20
+
+	DefaultDecimals = 1 // e.g. 10.4%
+	MaxDecimals     = 2
+
+	SkipFork        = "fork"         // Forks count towards their upstream's owner
+	SkipFetchFailed = "fetch_failed" // The repository's languages couldn't be fetched
+	SkipNoLanguages = "no_languages" // Every language of the repository is ignored, or it has none
 )
 
 type repository struct {
-	Name string `json:"name"`
-	Fork bool   `json:"fork"`
+	Name     string `json:"name"`
+	Fork     bool   `json:"fork"`
+	Language string `json:"language"` // Primary language reported by the repository listing
+	Stars    int    `json:"stargazers_count"`
+	Private  bool   `json:"private"`
+}
+
+// languageUsage aggregates a language's usage across all counted repositories.
+type languageUsage struct {
+	Bytes   int      // Total bytes of code
+	Repos   []string // Names of repositories containing the language
+	Stars   float64  // Byte share of each repository weighted by its stars
+	Primary int      // Number of repositories with this as their primary language
+	Lines   int      // Estimated lines of code
+}
+
+// Options controls how language statistics are scored and grouped.
+type Options struct {
+	Mode       string  // Scoring mode: bytes, geometric, stars or primary
+	LangsCount int     // Maximum number of entries, including "Other"
+	HideOther  bool    // Drop "Other" and renormalise the shown languages to 100%
+	MinPercent float64 // Languages below this percentage are folded into "Other"
+	Decimals   int     // Decimal places of the rounded percentages
+}
+
+// DefaultOptions returns the options used when a request doesn't override them.
+func DefaultOptions() Options {
+	return Options{
+		Mode:       "bytes",
+		LangsCount: DefaultLangsCount,
+		Decimals:   DefaultDecimals,
+	}
+}
+
+// Validate checks that the options are within their supported ranges.
+func (o Options) Validate() error {
+	switch o.Mode {
+	case "bytes", "geometric", "stars", "primary":
+	default:
+		return fmt.Errorf("mode must be one of bytes, geometric, stars or primary, got %q", o.Mode)
+	}
+
+	if o.LangsCount < MinLangsCount || o.LangsCount > MaxLangsCount {
+		return fmt.Errorf("langs_count must be between %d and %d", MinLangsCount, MaxLangsCount)
+	}
+
+	if !o.HideOther && o.LangsCount < MinLangsCountWithOther {
+		return fmt.Errorf("langs_count must be at least %d unless hide_other is set", MinLangsCountWithOther)
+	}
+
+	if !(o.MinPercent >= 0 && o.MinPercent <= 100) { // Also rejects NaN
+		return fmt.Errorf("min_percent must be between 0 and 100")
+	}
+
+	if o.Decimals < 0 || o.Decimals > MaxDecimals {
+		return fmt.Errorf("decimals must be between 0 and %d", MaxDecimals)
+	}
+
+	return nil
 }
 
 type Lang struct {
-	Name    string
-	Percent float64
-	Colour  string
+	Name      string
+	Percent   float64
+	Colour    string
+	Bytes     int      // Total bytes of code, zero in "primary" mode
+	Lines     int      // Estimated lines of code, derived from Bytes
+	RepoCount int      // Number of distinct repositories using the language
+	Score     float64  // Raw score for the selected mode, before conversion to a percentage
+	Others    []string // Languages folded into this entry, only set for "Other"
+}
+
+// IsOther reports whether the entry groups several languages together.
+func (l Lang) IsOther() bool {
+	return len(l.Others) > 0
+}
+
+// SkippedRepo is a repository left out of the statistics.
+type SkippedRepo struct {
+	Name   string
+	Reason string // SkipFork, SkipFetchFailed or SkipNoLanguages
+}
+
+// Report holds the language statistics along with how they were derived.
+type Report struct {
+	Languages        []Lang
+	RepoCount        int           // Repositories counted towards the statistics
+	Skipped          []SkippedRepo // Public repositories only
+	SkippedPrivate   int           // Private repositories left out, counted so their names stay private
+	IgnoredLanguages []string      // Sorted alphabetically
+	GeneratedAt      time.Time
+}
+
+// skip records a repository left out of the statistics, by name only when it's public.
+func (r *Report) skip(repo repository, reason string) {
+	if repo.Private {
+		r.SkippedPrivate++
+		return
+	}
+
+	r.Skipped = append(r.Skipped, SkippedRepo{repo.Name, reason})
 }
 
 // FetchStats retrieves language statistics for the authenticated user.
 // Excludes forked repositories and languages from the ignored languages file.
-func FetchStats(ignoredLanguagesData []byte, mode string) ([]Lang, error) {
+// The "primary" mode only uses the repository listing and skips the per-repository language calls.
+func FetchStats(ignoredLanguagesData []byte, opts Options) ([]Lang, error) {
+	report, err := FetchReport(ignoredLanguagesData, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return report.Languages, nil
+}
+
+// FetchReport retrieves language statistics like FetchStats, recording which repositories were counted or skipped.
+func FetchReport(ignoredLanguagesData []byte, opts Options) (Report, error) {
 	repos, err := fetchRepoNames()
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch repositories: %w", err)
+		return Report{}, fmt.Errorf("failed to fetch repositories: %w", err)
 	}
 
 	ignoredLanguages, err := parseIgnoredLanguages(ignoredLanguagesData)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse ignored languages: %w", err)
+		return Report{}, fmt.Errorf("failed to parse ignored languages: %w", err)
 	}
 
 	username, err := getUsername()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get authenticated user: %w", err)
+		return Report{}, fmt.Errorf("failed to get authenticated user: %w", err)
 	}
 
-	languageTotals := make(map[string]int)
-	languageFreq := make(map[string]int)
+	report := Report{IgnoredLanguages: sortedNames(ignoredLanguages)}
+	usage := make(map[string]languageUsage)
 
 	for _, repo := range repos {
 		if repo.Fork {
+			report.skip(repo, SkipFork)
 			continue
 		}
 
-		languages, err := fetchRepoLanguages(username, repo.Name)
-		if err != nil {
-			log.Printf("Warning: Failed to fetch languages for %s: %v", repo.Name, err)
+		var counted bool
+		if opts.Mode == "primary" {
+			counted = addPrimaryUsage(usage, repo, ignoredLanguages)
+		} else {
+			languages, err := fetchRepoLanguages(username, repo.Name)
+			if err != nil {
+				log.Printf("Warning: Failed to fetch languages for %s: %v", repo.Name, err)
+				report.skip(repo, SkipFetchFailed)
+				continue
+			}
+
+			counted = addRepoUsage(usage, repo, languages, ignoredLanguages)
+		}
+
+		if !counted {
+			report.skip(repo, SkipNoLanguages)
 			continue
 		}
+		report.RepoCount++
+	}
 
-		for lang, bytes := range languages {
-			if _, ignored := ignoredLanguages[lang]; ignored {
-				continue
-			}
+	addLineEstimates(usage)
+
+	report.Languages = calculateStats(usage, opts)
+	if err := addLanguageColours(report.Languages); err != nil {
+		return Report{}, fmt.Errorf("failed to add colours: %w", err)
+	}
+	report.GeneratedAt = time.Now().UTC()
+
+	return report, nil
+}
 
-			languageTotals[lang] += bytes
-			languageFreq[lang]++
+// addRepoUsage adds a repository's language breakdown to the aggregated usage, reporting whether
+// any of its languages counted. Star weighting spreads (stars + starBaseline) across the repository's
+// languages by byte share.
+func addRepoUsage(usage map[string]languageUsage, repo repository, languages map[string]int, ignoredLanguages map[string]struct{}) bool {
+	repoBytes := 0
+	for lang, bytes := range languages {
+		if _, ignored := ignoredLanguages[lang]; !ignored {
+			repoBytes += bytes
 		}
 	}
 
-	stats := calculateStats(languageTotals, languageFreq, mode)
-	if err := addLanguageColours(stats); err != nil {
-		return nil, fmt.Errorf("failed to add colours: %w", err)
+	weight := float64(repo.Stars + starBaseline)
+	counted := false
+
+	for lang, bytes := range languages {
+		if _, ignored := ignoredLanguages[lang]; ignored {
+			continue
+		}
+
+		u := usage[lang]
+		u.Bytes += bytes
+		u.Repos = append(u.Repos, repo.Name)
+		if repoBytes > 0 {
+			u.Stars += float64(bytes) / float64(repoBytes) * weight
+		}
+		if lang == repo.Language {
+			u.Primary++
+		}
+		usage[lang] = u
+		counted = true
+	}
+
+	return counted
+}
+
+// addPrimaryUsage counts a repository towards its primary language only, reporting whether it counted.
+func addPrimaryUsage(usage map[string]languageUsage, repo repository, ignoredLanguages map[string]struct{}) bool {
+	if _, ignored := ignoredLanguages[repo.Language]; ignored || repo.Language == "" {
+		return false
+	}
+
+	u := usage[repo.Language]
+	u.Repos = append(u.Repos, repo.Name)
+	u.Primary++
+	usage[repo.Language] = u
+
+	return true
+}
+
+// addLineEstimates converts each language's bytes to approximate lines of code
+// using its average bytes per line.
+func addLineEstimates(usage map[string]languageUsage) {
+	factors, err := loadBytesPerLine()
+	if err != nil {
+		log.Printf("Warning: Failed to load bytes per line: %v", err)
+		factors = make(map[string]float64)
+	}
+
+	for lang, u := range usage {
+		factor, exists := factors[lang]
+		if !exists || factor <= 0 {
+			factor = defaultBytesPerLine
+		}
+
+		u.Lines = int(math.Round(float64(u.Bytes) / factor))
+		usage[lang] = u
 	}
-	return stats, nil
 }
 
 func addLanguageColours(languages []Lang) error {
@@ -101,6 +303,18 @@ func loadLanguageColours() (map[string]string, error) {
 	return colours, nil
 }
 
+// loadBytesPerLine returns the average bytes per line of each language. The factors are hand-estimated
+// round figures until `go run ./cmd/fetchbytesperline`, which the monthly workflow runs, replaces them
+// with averages measured across linguist's code samples.
+func loadBytesPerLine() (map[string]float64, error) {
+	var factors map[string]float64
+	if err := json.Unmarshal(bytesPerLineJSON, &factors); err != nil {
+		return nil, fmt.Errorf("failed to parse embedded bytes per line: %w", err)
+	}
+
+	return factors, nil
+}
+
 func parseIgnoredLanguages(data []byte) (map[string]struct{}, error) {
 	var languages []string
 	if err := json.Unmarshal(data, &languages); err != nil {
@@ -114,3 +328,14 @@ func parseIgnoredLanguages(data []byte) (map[string]struct{}, error) {
 
 	return set, nil
 }
+
+// sortedNames returns the members of a set in alphabetical order.
+func sortedNames(set map[string]struct{}) []string {
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
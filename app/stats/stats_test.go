@@ -71,3 +71,128 @@ func TestAddLanguageColours(t *testing.T) {
 		t.Error("Java language should have a colour assigned")
 	}
 }
+
+func TestAddRepoUsage(t *testing.T) {
+	usage := make(map[string]languageUsage)
+	ignored := map[string]struct{}{"HTML": {}}
+
+	addRepoUsage(usage, repository{Name: "api", Language: "Go", Stars: 3},
+		map[string]int{"Go": 750, "Shell": 250, "HTML": 5000}, ignored)
+	addRepoUsage(usage, repository{Name: "cli", Language: "Go", Stars: 0},
+		map[string]int{"Go": 100}, ignored)
+
+	if addRepoUsage(usage, repository{Name: "site", Language: "HTML"}, map[string]int{"HTML": 900}, ignored) {
+		t.Error("a repository with only ignored languages shouldn't count")
+	}
+
+	if _, exists := usage["HTML"]; exists {
+		t.Error("HTML should be ignored")
+	}
+
+	goUsage := usage["Go"]
+	if goUsage.Bytes != 850 || len(goUsage.Repos) != 2 || goUsage.Primary != 2 {
+		t.Errorf("Go usage = %+v, want 850 bytes across 2 primary repos", goUsage)
+	}
+
+	// (3 stars + baseline) * 0.75 share + (0 stars + baseline) * 1.0 share
+	if goUsage.Stars != 4 {
+		t.Errorf("Go star score = %v, want 4", goUsage.Stars)
+	}
+
+	if shell := usage["Shell"]; shell.Stars != 1 || shell.Primary != 0 {
+		t.Errorf("Shell usage = %+v, want star score 1 and no primary repos", shell)
+	}
+}
+
+func TestAddPrimaryUsage(t *testing.T) {
+	usage := make(map[string]languageUsage)
+	ignored := map[string]struct{}{"HTML": {}}
+
+	for _, tt := range []struct {
+		repo    repository
+		counted bool
+	}{
+		{repository{Name: "a", Language: "Go"}, true},
+		{repository{Name: "b", Language: "Go"}, true},
+		{repository{Name: "c", Language: "HTML"}, false},
+		{repository{Name: "d", Language: ""}, false},
+	} {
+		if counted := addPrimaryUsage(usage, tt.repo, ignored); counted != tt.counted {
+			t.Errorf("addPrimaryUsage(%s) = %v, want %v", tt.repo.Name, counted, tt.counted)
+		}
+	}
+
+	if len(usage) != 1 {
+		t.Errorf("got %d languages, want 1", len(usage))
+	}
+
+	if goUsage := usage["Go"]; goUsage.Primary != 2 || len(goUsage.Repos) != 2 {
+		t.Errorf("Go usage = %+v, want 2 primary repos", goUsage)
+	}
+}
+
+func TestOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		wantErr bool
+	}{
+		{"Defaults", DefaultOptions(), false},
+		{"Maximum languages", Options{Mode: "bytes", LangsCount: MaxLangsCount, Decimals: MaxDecimals}, false},
+		{"Primary mode", Options{Mode: "primary", LangsCount: 6}, false},
+		{"Unknown mode", Options{Mode: "star", LangsCount: 6}, true},
+		{"Empty mode", Options{LangsCount: 6}, true},
+		{"Too few languages", Options{Mode: "bytes", LangsCount: 0}, true},
+		{"Single language beside Other", Options{Mode: "bytes", LangsCount: 1}, true},
+		{"Single language without Other", Options{Mode: "bytes", LangsCount: 1, HideOther: true}, false},
+		{"Too many languages", Options{Mode: "bytes", LangsCount: MaxLangsCount + 1}, true},
+		{"Negative minimum percent", Options{Mode: "bytes", LangsCount: 6, MinPercent: -1}, true},
+		{"Minimum percent above 100", Options{Mode: "bytes", LangsCount: 6, MinPercent: 101}, true},
+		{"Negative decimals", Options{Mode: "bytes", LangsCount: 6, Decimals: -1}, true},
+		{"Too many decimals", Options{Mode: "bytes", LangsCount: 6, Decimals: MaxDecimals + 1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opts.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReportSkip(t *testing.T) {
+	var report Report
+	report.skip(repository{Name: "gin", Fork: true}, SkipFork)
+	report.skip(repository{Name: "secret-fork", Fork: true, Private: true}, SkipFork)
+	report.skip(repository{Name: "secret-empty", Private: true}, SkipNoLanguages)
+
+	if len(report.Skipped) != 1 || report.Skipped[0] != (SkippedRepo{"gin", SkipFork}) {
+		t.Errorf("Skipped = %+v, want only the public fork", report.Skipped)
+	}
+	if report.SkippedPrivate != 2 {
+		t.Errorf("SkippedPrivate = %d, want 2", report.SkippedPrivate)
+	}
+}
+
+func TestAddLineEstimates(t *testing.T) {
+	factors, err := loadBytesPerLine()
+	if err != nil {
+		t.Fatalf("loadBytesPerLine() error = %v", err)
+	}
+
+	usage := map[string]languageUsage{
+		"Go":              {Bytes: 28_000},
+		"UnknownLanguage": {Bytes: 3_200},
+	}
+
+	addLineEstimates(usage)
+
+	if expected := int(28_000 / factors["Go"]); usage["Go"].Lines != expected {
+		t.Errorf("Go lines = %d, want %d", usage["Go"].Lines, expected)
+	}
+
+	if expected := 3_200 / defaultBytesPerLine; usage["UnknownLanguage"].Lines != expected {
+		t.Errorf("Unknown language lines = %d, want %d", usage["UnknownLanguage"].Lines, expected)
+	}
+}
@@ -0,0 +1,156 @@
+package svg
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	DefaultBadgeStyle = "flat"
+
+	notFoundBadgeColour = "#9F9F9F"
+)
+
+// badgeStyle holds the metrics of a badge style, following the shields.io styles of the same name.
+type badgeStyle struct {
+	Height        float64
+	Font          font // Font of the label, the message uses its bold variant when BoldMessage is set
+	Padding       float64
+	Radius        float64
+	TextY         float64 // Baseline of the text
+	LetterSpacing float64
+	Gradient      bool // Overlay a subtle vertical gradient
+	Uppercase     bool
+	BoldMessage   bool
+}
+
+var badgeStyles = map[string]badgeStyle{
+	"flat":          {Height: 20, Font: font{Size: 11}, Padding: 6, Radius: 3, TextY: 14, Gradient: true},
+	"flat-square":   {Height: 20, Font: font{Size: 11}, Padding: 6, TextY: 14},
+	"for-the-badge": {Height: 28, Font: font{Size: 10}, Padding: 9, TextY: 18, LetterSpacing: 1, Uppercase: true, BoldMessage: true},
+}
+
+// BadgeOptions controls how a badge is rendered.
+type BadgeOptions struct {
+	Label   string // Left-hand text, empty for a message-only badge
+	Message string
+	Colour  string // Background of the message, e.g. the language colour
+	Style   string // flat, flat-square or for-the-badge
+	Theme   string // Colours of the label
+	Colours Theme  // Overrides layered on top of the theme, only Background and Text are used
+}
+
+// DefaultBadgeOptions returns the options used when a request doesn't override them.
+func DefaultBadgeOptions() BadgeOptions {
+	return BadgeOptions{
+		Colour: notFoundBadgeColour,
+		Style:  DefaultBadgeStyle,
+		Theme:  DefaultTheme,
+	}
+}
+
+// Validate checks that the options contain supported values.
+func (o BadgeOptions) Validate() error {
+	if _, exists := badgeStyles[o.Style]; !exists {
+		return fmt.Errorf("style must be one of flat, flat-square or for-the-badge, got %q", o.Style)
+	}
+
+	for _, override := range []struct{ name, colour string }{
+		{"bg_color", o.Colours.Background},
+		{"text_color", o.Colours.Text},
+	} {
+		if override.colour == "" {
+			continue
+		}
+
+		if _, err := ParseColour(override.colour); err != nil {
+			return fmt.Errorf("%s: %w", override.name, err)
+		}
+	}
+
+	return nil
+}
+
+// BadgeData is the data of the badge template.
+type BadgeData struct {
+	Theme       Theme
+	Title       string // Accessible name, the label or the message when there's no label
+	Description string // Accessible summary, e.g. "Go: 45.5%"
+
+	Width         float64
+	Height        float64
+	Radius        float64
+	Gradient      bool
+	FontSize      float64
+	LetterSpacing float64
+	TextY         float64
+	BoldMessage   bool
+
+	Label        string
+	LabelWidth   float64
+	LabelX       float64 // Centre of the label text
+	Message      string
+	MessageX     float64 // Centre of the message text
+	MessageWidth float64
+	Colour       string
+	MessageText  string // Black or white, whichever reads better on Colour
+}
+
+// GenerateBadge creates a two-part badge SVG with the label in theme colours and the message on opts.Colour.
+func GenerateBadge(opts BadgeOptions) (string, error) {
+	style, exists := badgeStyles[opts.Style]
+	if !exists {
+		style = badgeStyles[DefaultBadgeStyle]
+	}
+
+	colour, err := ParseColour(opts.Colour)
+	if err != nil {
+		colour = notFoundBadgeColour
+	}
+
+	label, message := opts.Label, opts.Message
+	if style.Uppercase {
+		label, message = strings.ToUpper(label), strings.ToUpper(message)
+	}
+
+	data := BadgeData{
+		Theme:         GetTheme(opts.Theme).withOverrides(opts.Colours),
+		Title:         opts.Label,
+		Description:   opts.Message,
+		Height:        style.Height,
+		Radius:        style.Radius,
+		Gradient:      style.Gradient,
+		FontSize:      style.Font.Size,
+		LetterSpacing: style.LetterSpacing,
+		TextY:         style.TextY,
+		BoldMessage:   style.BoldMessage,
+		Label:         label,
+		Message:       message,
+		Colour:        colour,
+		MessageText:   textColourOn(colour),
+	}
+
+	if label == "" {
+		data.Title = opts.Message
+	} else {
+		data.Description = opts.Label + ": " + opts.Message
+		data.LabelWidth = style.sectionWidth(label, style.Font)
+	}
+
+	messageFont := style.Font
+	messageFont.Bold = style.BoldMessage
+	data.MessageWidth = style.sectionWidth(message, messageFont)
+
+	data.Width = data.LabelWidth + data.MessageWidth
+	data.LabelX = round2(data.LabelWidth / 2)
+	data.MessageX = round2(data.LabelWidth + data.MessageWidth/2)
+
+	return executeTemplate("badge.svg", "", GetLocale(DefaultLocale), data)
+}
+
+// sectionWidth returns the width of a badge section holding text, rounded up to whole pixels.
+func (s badgeStyle) sectionWidth(text string, f font) float64 {
+	spacing := s.LetterSpacing * float64(len([]rune(text)))
+	return math.Ceil(f.width(text) + spacing + 2*s.Padding)
+}
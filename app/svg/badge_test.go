@@ -0,0 +1,96 @@
+package svg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateBadge_Golden(t *testing.T) {
+	tests := []struct {
+		name  string
+		style string
+		theme string
+	}{
+		{"badge_flat", "flat", DefaultTheme},
+		{"badge_flat_square", "flat-square", "light"},
+		{"badge_for_the_badge", "for-the-badge", DefaultTheme},
+		{"badge_auto", "flat", AutoTheme},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultBadgeOptions()
+			opts.Label, opts.Message, opts.Colour = "Go", "45.5%", "#00ADD8"
+			opts.Style = tt.style
+			opts.Theme = tt.theme
+
+			result, err := GenerateBadge(opts)
+			if err != nil {
+				t.Fatalf("GenerateBadge() error = %v", err)
+			}
+			compareGolden(t, tt.name, result)
+		})
+	}
+}
+
+func TestGenerateBadge_Widths(t *testing.T) {
+	opts := DefaultBadgeOptions()
+	opts.Label, opts.Message, opts.Colour = "JavaScript", "15.8%", "#f1e05a"
+
+	short, _ := GenerateBadge(BadgeOptions{Label: "Python", Message: "8.5%", Colour: "#3572A5", Style: "flat"})
+	long, err := GenerateBadge(opts)
+	if err != nil {
+		t.Fatalf("GenerateBadge() error = %v", err)
+	}
+
+	// "JavaScript" is 51.3px at 11px and "Python" 34.2px, plus 6px padding either side
+	if !strings.Contains(long, `<rect class="badge-label" width="64"`) {
+		t.Error("label section isn't sized to its text")
+	}
+	if !strings.Contains(short, `<rect class="badge-label" width="47"`) {
+		t.Error("short label section isn't sized to its text")
+	}
+
+	// Yellow needs dark text, blue needs light text
+	if !strings.Contains(long, `fill="#000000"`) || !strings.Contains(short, `fill="#FFFFFF"`) {
+		t.Error("message text doesn't contrast with its background")
+	}
+}
+
+func TestGenerateBadge_NoLabel(t *testing.T) {
+	result, err := GenerateBadge(BadgeOptions{Message: "Go", Colour: "#00ADD8", Style: "flat"})
+	if err != nil {
+		t.Fatalf("GenerateBadge() error = %v", err)
+	}
+
+	if strings.Contains(result, "badge-label\"") {
+		t.Error("badge without a label draws the label section")
+	}
+	if !strings.Contains(result, `<title id="card-title">Go</title>`) {
+		t.Error("badge without a label isn't named after its message")
+	}
+}
+
+func TestBadgeOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*BadgeOptions)
+		wantErr bool
+	}{
+		{"Defaults", func(o *BadgeOptions) {}, false},
+		{"For the badge", func(o *BadgeOptions) { o.Style = "for-the-badge" }, false},
+		{"Unknown style", func(o *BadgeOptions) { o.Style = "plastic" }, true},
+		{"Valid bg_color", func(o *BadgeOptions) { o.Colours.Background = "555" }, false},
+		{"Invalid text_color", func(o *BadgeOptions) { o.Colours.Text = "url(#x)" }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultBadgeOptions()
+			tt.modify(&opts)
+			if err := opts.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package svg
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"go-readme-stats/app/stats"
+)
+
+const (
+	fullCircle  = 360.0
+	labelHeight = 10.0 // Height of the label text box
+)
+
+// Segment is a single language's slice of a circular chart.
+type Segment struct {
+	Path  string  // SVG path data
+	Start float64 // Angle in degrees, clockwise from 12 o'clock
+	End   float64
+	Lang  stats.Lang
+
+	Label       string // In-slice label, only used by the pie layout
+	LabelX      float64
+	LabelY      float64
+	LabelColour string
+	ShowLabel   bool // False when the label would overflow its slice
+}
+
+// computeSegments splits a circle centred on (cx, cy) into one segment per language, clockwise from 12 o'clock.
+// Segments are rings between inner and outer, or pie slices when inner is zero.
+func computeSegments(languages []stats.Lang, cx, cy, outer, inner float64) []Segment {
+	var total float64
+	for _, lang := range languages {
+		total += lang.Percent
+	}
+
+	segments := make([]Segment, 0, len(languages))
+	if total <= 0 {
+		return segments
+	}
+
+	start := 0.0
+	for _, lang := range languages {
+		end := start + lang.Percent/total*fullCircle
+		segments = append(segments, Segment{
+			Path:  arcPath(cx, cy, outer, inner, start, end),
+			Start: start,
+			End:   end,
+			Lang:  lang,
+		})
+		start = end
+	}
+
+	return segments
+}
+
+// arcPath returns the path of a segment between two angles in degrees, measured clockwise from 12 o'clock.
+// A full circle is drawn as two half arcs, since an arc whose start and end points coincide renders nothing.
+func arcPath(cx, cy, outer, inner, start, end float64) string {
+	if end-start >= fullCircle-1e-9 {
+		path := circlePath(cx, cy, outer)
+		if inner > 0 {
+			path += " " + circlePath(cx, cy, inner) // Cut out with fill-rule="evenodd"
+		}
+		return path
+	}
+
+	largeArc := 0
+	if end-start > fullCircle/2 {
+		largeArc = 1
+	}
+
+	x0, y0 := polar(cx, cy, outer, start)
+	x1, y1 := polar(cx, cy, outer, end)
+	path := fmt.Sprintf("M %s %s A %s %s 0 %d 1 %s %s",
+		num(x0), num(y0), num(outer), num(outer), largeArc, num(x1), num(y1))
+
+	if inner <= 0 {
+		return path + fmt.Sprintf(" L %s %s Z", num(cx), num(cy))
+	}
+
+	x2, y2 := polar(cx, cy, inner, end)
+	x3, y3 := polar(cx, cy, inner, start)
+	return path + fmt.Sprintf(" L %s %s A %s %s 0 %d 0 %s %s Z",
+		num(x2), num(y2), num(inner), num(inner), largeArc, num(x3), num(y3))
+}
+
+// circlePath returns a closed circle made of two half arcs, starting at 12 o'clock.
+func circlePath(cx, cy, r float64) string {
+	return fmt.Sprintf("M %s %s A %s %s 0 1 1 %s %s A %s %s 0 1 1 %s %s Z",
+		num(cx), num(cy-r), num(r), num(r), num(cx), num(cy+r), num(r), num(r), num(cx), num(cy-r))
+}
+
+// polar converts an angle in degrees, clockwise from 12 o'clock, to a point on the circle.
+func polar(cx, cy, r, degrees float64) (float64, float64) {
+	radians := (degrees - 90) * math.Pi / 180
+	return cx + r*math.Cos(radians), cy + r*math.Sin(radians)
+}
+
+// num formats a coordinate with at most two decimals.
+func num(v float64) string {
+	return strconv.FormatFloat(round2(v), 'f', -1, 64)
+}
+
+// round2 rounds a coordinate to two decimals.
+func round2(v float64) float64 {
+	v = math.Round(v*100) / 100
+	if v == 0 {
+		return 0 // Avoid "-0"
+	}
+	return v
+}
+
+// placeLabels positions each segment's label at the centroid of its pie slice, and only shows labels
+// whose text box fits entirely inside the slice.
+func placeLabels(segments []Segment, cx, cy, radius float64, opts Options) {
+	for i := range segments {
+		segment := &segments[i]
+		sweep := segment.End - segment.Start
+
+		// Centroid of a circular sector: 2r·sin(α) / 3α from the centre, where α is half the sweep
+		alpha := sweep / 2 * math.Pi / 180
+		distance := 0.0
+		if alpha > 0 {
+			distance = 2 * radius * math.Sin(alpha) / (3 * alpha)
+		}
+
+		segment.Label = opts.format(segment.Lang)
+		x, y := polar(cx, cy, distance, segment.Start+sweep/2)
+		segment.LabelX, segment.LabelY = round2(x), round2(y)
+		segment.LabelColour = textColourOn(segment.Lang.Colour)
+
+		width := sliceLabelFont.width(segment.Label)
+		segment.ShowLabel = boxInSector(segment.LabelX, segment.LabelY, width, labelHeight, cx, cy, radius, segment.Start, segment.End)
+	}
+}
+
+// boxInSector reports whether a box centred on (x, y) lies within the sector between two angles.
+// Points are sampled along the box outline, since sectors over 180° aren't convex.
+func boxInSector(x, y, width, height, cx, cy, radius, start, end float64) bool {
+	const steps = 4
+	left, top := x-width/2, y-height/2
+
+	for i := 0; i <= steps; i++ {
+		t := float64(i) / steps
+		for _, point := range [][2]float64{
+			{left + t*width, top},
+			{left + t*width, top + height},
+			{left, top + t*height},
+			{left + width, top + t*height},
+		} {
+			if !pointInSector(point[0], point[1], cx, cy, radius, start, end) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// pointInSector reports whether a point lies within the sector between two angles.
+func pointInSector(x, y, cx, cy, radius, start, end float64) bool {
+	dx, dy := x-cx, y-cy
+	if math.Hypot(dx, dy) > radius {
+		return false
+	}
+
+	if end-start >= fullCircle-1e-9 {
+		return true
+	}
+
+	// Angle clockwise from 12 o'clock, in the range [0, 360)
+	angle := math.Mod(math.Atan2(dy, dx)*180/math.Pi+90+fullCircle, fullCircle)
+	return angle >= start && angle <= end
+}
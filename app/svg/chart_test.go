@@ -0,0 +1,133 @@
+package svg
+
+import (
+	"go-readme-stats/app/stats"
+	"testing"
+)
+
+func TestArcPath(t *testing.T) {
+	tests := []struct {
+		name       string
+		inner      float64
+		start, end float64
+		expected   string
+	}{
+		{
+			name:     "Quarter ring",
+			inner:    5,
+			start:    0,
+			end:      90,
+			expected: "M 10 0 A 10 10 0 0 1 20 10 L 15 10 A 5 5 0 0 0 10 5 Z",
+		},
+		{
+			name:     "Large ring segment",
+			inner:    5,
+			start:    0,
+			end:      270,
+			expected: "M 10 0 A 10 10 0 1 1 0 10 L 5 10 A 5 5 0 1 0 10 5 Z",
+		},
+		{
+			name:     "Exactly half is not a large arc",
+			inner:    0,
+			start:    0,
+			end:      180,
+			expected: "M 10 0 A 10 10 0 0 1 10 20 L 10 10 Z",
+		},
+		{
+			name:     "Full ring",
+			inner:    5,
+			start:    0,
+			end:      360,
+			expected: "M 10 0 A 10 10 0 1 1 10 20 A 10 10 0 1 1 10 0 Z M 10 5 A 5 5 0 1 1 10 15 A 5 5 0 1 1 10 5 Z",
+		},
+		{
+			name:     "Full pie",
+			inner:    0,
+			start:    0,
+			end:      360,
+			expected: "M 10 0 A 10 10 0 1 1 10 20 A 10 10 0 1 1 10 0 Z",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := arcPath(10, 10, 10, tt.inner, tt.start, tt.end); result != tt.expected {
+				t.Errorf("arcPath() = %s, want %s", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestComputeSegments(t *testing.T) {
+	languages := []stats.Lang{
+		{Name: "Go", Percent: 75},
+		{Name: "Rust", Percent: 25},
+	}
+
+	segments := computeSegments(languages, 10, 10, 10, 0)
+	if len(segments) != 2 {
+		t.Fatalf("got %d segments, want 2", len(segments))
+	}
+
+	expected := []string{
+		"M 10 0 A 10 10 0 1 1 0 10 L 10 10 Z",
+		"M 0 10 A 10 10 0 0 1 10 0 L 10 10 Z",
+	}
+
+	for i, segment := range segments {
+		if segment.Path != expected[i] {
+			t.Errorf("[%d] path = %s, want %s", i, segment.Path, expected[i])
+		}
+	}
+}
+
+func TestComputeSegments_Empty(t *testing.T) {
+	if segments := computeSegments(nil, 10, 10, 10, 5); len(segments) != 0 {
+		t.Errorf("got %d segments for no languages, want 0", len(segments))
+	}
+}
+
+func TestPlaceLabels(t *testing.T) {
+	languages := []stats.Lang{
+		{Name: "Go", Percent: 60, Colour: "#00ADD8"},
+		{Name: "C", Percent: 37, Colour: "#555555"},
+		{Name: "Shell", Percent: 3, Colour: "#89e051"},
+	}
+
+	segments := computeSegments(languages, 50, 50, 50, 0)
+	placeLabels(segments, 50, 50, 50, DefaultOptions())
+
+	expected := []struct {
+		label  string
+		show   bool
+		colour string
+	}{
+		{"60%", true, "#000000"},
+		{"37%", true, "#FFFFFF"},
+		{"3%", false, "#000000"}, // Too thin to fit
+	}
+
+	for i, segment := range segments {
+		if segment.Label != expected[i].label {
+			t.Errorf("[%d] label = %s, want %s", i, segment.Label, expected[i].label)
+		}
+		if segment.ShowLabel != expected[i].show {
+			t.Errorf("[%d] ShowLabel = %v, want %v", i, segment.ShowLabel, expected[i].show)
+		}
+		if segment.LabelColour != expected[i].colour {
+			t.Errorf("[%d] label colour = %s, want %s", i, segment.LabelColour, expected[i].colour)
+		}
+		if !pointInSector(segment.LabelX, segment.LabelY, 50, 50, 50, segment.Start, segment.End) {
+			t.Errorf("[%d] label (%v, %v) is outside its slice", i, segment.LabelX, segment.LabelY)
+		}
+	}
+}
+
+func TestPlaceLabels_FullCircleCentred(t *testing.T) {
+	segments := computeSegments([]stats.Lang{{Name: "Go", Percent: 100}}, 50, 50, 50, 0)
+	placeLabels(segments, 50, 50, 50, DefaultOptions())
+
+	if segments[0].LabelX != 50 || segments[0].LabelY != 50 || !segments[0].ShowLabel {
+		t.Errorf("label = (%v, %v) shown %v, want centred and shown", segments[0].LabelX, segments[0].LabelY, segments[0].ShowLabel)
+	}
+}
@@ -0,0 +1,132 @@
+package svg
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go-readme-stats/app/stats"
+)
+
+var hexColourPattern = regexp.MustCompile(`^#?([0-9a-fA-F]{3}|[0-9a-fA-F]{4}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+
+// rgb is a colour with channels in the range 0-1.
+type rgb struct {
+	R, G, B float64
+}
+
+// ParseColour validates a user-supplied colour and returns it as #RRGGBB, or #RRGGBBAA when it has alpha.
+// Accepts hex colours with 3, 4, 6 or 8 digits, with or without a leading #, and CSS named colours.
+// Anything else is rejected, so the result is always safe to embed in the SVG's style block.
+func ParseColour(value string) (string, error) {
+	if hex, exists := namedColours[strings.ToLower(value)]; exists {
+		return hex, nil
+	}
+
+	if !hexColourPattern.MatchString(value) {
+		return "", fmt.Errorf("%q is not a hex colour or CSS colour name", value)
+	}
+
+	hex := strings.ToUpper(strings.TrimPrefix(value, "#"))
+	if len(hex) <= 4 {
+		expanded := make([]byte, 0, len(hex)*2)
+		for i := range len(hex) {
+			expanded = append(expanded, hex[i], hex[i])
+		}
+		hex = string(expanded)
+	}
+
+	return "#" + hex, nil
+}
+
+// applyOtherColour returns a copy of languages with the "Other" entry recoloured.
+// An empty colour leaves the languages unchanged.
+func applyOtherColour(languages []stats.Lang, colour string) []stats.Lang {
+	if colour == "" {
+		return languages
+	}
+
+	recoloured := make([]stats.Lang, len(languages))
+	for i, lang := range languages {
+		if lang.IsOther() {
+			lang.Colour = colour
+		}
+		recoloured[i] = lang
+	}
+
+	return recoloured
+}
+
+// parseHex parses a #RGB or #RRGGBB colour, ignoring any alpha channel.
+func parseHex(colour string) (rgb, error) {
+	hex := strings.TrimPrefix(colour, "#")
+
+	switch len(hex) {
+	case 3, 4:
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	case 6, 8:
+		hex = hex[:6]
+	default:
+		return rgb{}, fmt.Errorf("invalid hex colour %q", colour)
+	}
+
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return rgb{}, fmt.Errorf("invalid hex colour %q", colour)
+	}
+
+	return rgb{
+		R: float64(value>>16&0xFF) / 255,
+		G: float64(value>>8&0xFF) / 255,
+		B: float64(value&0xFF) / 255,
+	}, nil
+}
+
+// mustParseHex parses a colour already validated by ParseColour.
+func mustParseHex(colour string) rgb {
+	c, err := parseHex(colour)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// contrastRatio returns the WCAG contrast ratio between two colours, from 1 to 21.
+func contrastRatio(a, b rgb) float64 {
+	lighter, darker := a.luminance(), b.luminance()
+	if darker > lighter {
+		lighter, darker = darker, lighter
+	}
+
+	return (lighter + 0.05) / (darker + 0.05)
+}
+
+// luminance returns the WCAG relative luminance of the colour.
+func (c rgb) luminance() float64 {
+	linear := func(v float64) float64 {
+		if v <= 0.03928 {
+			return v / 12.92
+		}
+		return math.Pow((v+0.055)/1.055, 2.4)
+	}
+
+	return 0.2126*linear(c.R) + 0.7152*linear(c.G) + 0.0722*linear(c.B)
+}
+
+// textColourOn returns black or white, whichever is more legible on the given background.
+// Unparseable backgrounds get white text.
+func textColourOn(background string) string {
+	c, err := parseHex(background)
+	if err != nil {
+		return "#FFFFFF"
+	}
+
+	// Black and white have equal contrast against a background with luminance ~0.179
+	if c.luminance() > 0.179 {
+		return "#000000"
+	}
+
+	return "#FFFFFF"
+}
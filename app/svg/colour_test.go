@@ -0,0 +1,126 @@
+package svg
+
+import (
+	"go-readme-stats/app/stats"
+	"math"
+	"testing"
+)
+
+func TestParseColour(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{"0D1117", "#0D1117", false},
+		{"#0d1117", "#0D1117", false},
+		{"fff", "#FFFFFF", false},
+		{"#f0a8", "#FF00AA88", false},
+		{"0D111780", "#0D111780", false},
+		{"RebeccaPurple", "#663399", false},
+		{"white", "#FFFFFF", false},
+		{"", "", true},
+		{"12345", "", true},
+		{"#GGGGGG", "", true},
+		{"notacolour", "", true},
+		{"red;}</style><script>", "", true},
+		{"url(#x)", "", true},
+		{"rgb(0,0,0)", "", true},
+	}
+
+	for _, tt := range tests {
+		result, err := ParseColour(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseColour(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if result != tt.expected {
+			t.Errorf("ParseColour(%q) = %s, want %s", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestApplyOtherColour(t *testing.T) {
+	languages := []stats.Lang{
+		{Name: "Go", Colour: "#00ADD8"},
+		{Name: "Other (3)", Colour: "#F0F6FC", Others: []string{"C", "Lua", "Zig"}},
+	}
+
+	recoloured := applyOtherColour(languages, "#FF0000")
+
+	if recoloured[0].Colour != "#00ADD8" {
+		t.Errorf("Go colour = %s, want unchanged", recoloured[0].Colour)
+	}
+
+	if recoloured[1].Colour != "#FF0000" {
+		t.Errorf("Other colour = %s, want #FF0000", recoloured[1].Colour)
+	}
+
+	if languages[1].Colour != "#F0F6FC" {
+		t.Error("applyOtherColour() modified the input slice")
+	}
+}
+
+func TestParseHex(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected rgb
+		wantErr  bool
+	}{
+		{"#FFFFFF", rgb{1, 1, 1}, false},
+		{"#000", rgb{0, 0, 0}, false},
+		{"#F00F", rgb{1, 0, 0}, false},
+		{"00FF0080", rgb{0, 1, 0}, false},
+		{"#12345", rgb{}, true},
+		{"#GGGGGG", rgb{}, true},
+	}
+
+	for _, tt := range tests {
+		result, err := parseHex(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseHex(%s) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if result != tt.expected {
+			t.Errorf("parseHex(%s) = %+v, want %+v", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestTextColourOn(t *testing.T) {
+	tests := []struct {
+		background string
+		expected   string
+	}{
+		{"#FFFFFF", "#000000"},
+		{"#f1e05a", "#000000"}, // JavaScript yellow
+		{"#0D1117", "#FFFFFF"},
+		{"#3572A5", "#FFFFFF"}, // Python blue
+		{"invalid", "#FFFFFF"},
+	}
+
+	for _, tt := range tests {
+		if result := textColourOn(tt.background); result != tt.expected {
+			t.Errorf("textColourOn(%s) = %s, want %s", tt.background, result, tt.expected)
+		}
+	}
+}
+
+func TestContrastRatio(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected float64
+	}{
+		{"#000000", "#FFFFFF", 21},
+		{"#FFFFFF", "#000000", 21},
+		{"#777777", "#777777", 1},
+		{"#F0F6FC", "#0D1117", 17.4},
+	}
+
+	for _, tt := range tests {
+		ratio := contrastRatio(mustParseHex(tt.a), mustParseHex(tt.b))
+		if math.Abs(ratio-tt.expected) > 0.05 {
+			t.Errorf("contrastRatio(%s, %s) = %.2f, want %.1f", tt.a, tt.b, ratio, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package svg
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"go-readme-stats/app/stats"
+)
+
+const (
+	DefaultMinContrast = 2.0 // Enough to tell a segment from the card, well below the 4.5 needed for text
+	MaxMinContrast     = 7.0 // WCAG AAA, higher ratios can't be met for mid-luminance backgrounds
+
+	lightnessStep = 0.005
+)
+
+// hsl is a colour as hue in degrees and saturation and lightness in the range 0-1.
+type hsl struct {
+	H, S, L float64
+}
+
+// toHSL converts the colour to hue, saturation and lightness.
+func (c rgb) toHSL() hsl {
+	high := math.Max(c.R, math.Max(c.G, c.B))
+	low := math.Min(c.R, math.Min(c.G, c.B))
+	l := (high + low) / 2
+
+	if high == low {
+		return hsl{L: l}
+	}
+
+	delta := high - low
+	s := delta / (1 - math.Abs(2*l-1))
+
+	var h float64
+	switch high {
+	case c.R:
+		h = math.Mod((c.G-c.B)/delta, 6)
+	case c.G:
+		h = (c.B-c.R)/delta + 2
+	default:
+		h = (c.R-c.G)/delta + 4
+	}
+
+	return hsl{H: math.Mod(h*60+360, 360), S: s, L: l}
+}
+
+// toRGB converts the colour back to red, green and blue.
+func (c hsl) toRGB() rgb {
+	chroma := (1 - math.Abs(2*c.L-1)) * c.S
+	x := chroma * (1 - math.Abs(math.Mod(c.H/60, 2)-1))
+	m := c.L - chroma/2
+
+	var r, g, b float64
+	switch {
+	case c.H < 60:
+		r, g = chroma, x
+	case c.H < 120:
+		r, g = x, chroma
+	case c.H < 180:
+		g, b = chroma, x
+	case c.H < 240:
+		g, b = x, chroma
+	case c.H < 300:
+		r, b = x, chroma
+	default:
+		r, b = chroma, x
+	}
+
+	return rgb{R: r + m, G: g + m, B: b + m}
+}
+
+// hex formats the colour as #RRGGBB.
+func (c rgb) hex() string {
+	channel := func(v float64) int {
+		return int(math.Round(math.Max(0, math.Min(1, v)) * 255))
+	}
+
+	return fmt.Sprintf("#%02X%02X%02X", channel(c.R), channel(c.G), channel(c.B))
+}
+
+// applyContrast returns a copy of languages with each colour adjusted to reach minContrast against every background.
+// A minContrast of zero leaves the languages unchanged.
+func applyContrast(languages []stats.Lang, backgrounds []string, minContrast float64) []stats.Lang {
+	if minContrast <= 0 {
+		return languages
+	}
+
+	parsed := make([]rgb, 0, len(backgrounds))
+	for _, background := range backgrounds {
+		if c, err := parseHex(background); err == nil {
+			parsed = append(parsed, c)
+		}
+	}
+
+	adjusted := make([]stats.Lang, len(languages))
+	for i, lang := range languages {
+		lang.Colour = ensureContrast(lang.Colour, parsed, minContrast)
+		adjusted[i] = lang
+	}
+
+	return adjusted
+}
+
+// ensureContrast nudges the colour's HSL lightness as little as possible until its contrast
+// against every background is at least minContrast, keeping hue, saturation and any alpha.
+// When no lightness meets the ratio, the one with the best worst-case contrast is used.
+// Colours that already meet the ratio, or can't be parsed, are returned unchanged.
+func ensureContrast(colour string, backgrounds []rgb, minContrast float64) string {
+	c, err := parseHex(colour)
+	if err != nil || len(backgrounds) == 0 || worstContrast(c, backgrounds) >= minContrast {
+		return colour
+	}
+
+	alpha := ""
+	if hex := strings.TrimPrefix(colour, "#"); len(hex) == 8 {
+		alpha = strings.ToUpper(hex[6:])
+	}
+
+	original := c.toHSL()
+	candidates := make([]float64, 0, int(1/lightnessStep)+1)
+	for l := 0.0; l <= 1; l += lightnessStep {
+		candidates = append(candidates, l)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return math.Abs(candidates[i]-original.L) < math.Abs(candidates[j]-original.L)
+	})
+
+	best, bestContrast := c, 0.0
+	for _, l := range candidates {
+		candidate := hsl{H: original.H, S: original.S, L: l}.toRGB()
+		contrast := worstContrast(candidate, backgrounds)
+		if contrast >= minContrast {
+			return candidate.hex() + alpha
+		}
+		if contrast > bestContrast {
+			best, bestContrast = candidate, contrast
+		}
+	}
+
+	return best.hex() + alpha
+}
+
+// worstContrast returns the lowest contrast ratio between the colour and any of the backgrounds.
+func worstContrast(c rgb, backgrounds []rgb) float64 {
+	worst := math.Inf(1)
+	for _, background := range backgrounds {
+		worst = math.Min(worst, contrastRatio(c, background))
+	}
+
+	return worst
+}
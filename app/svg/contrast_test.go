@@ -0,0 +1,131 @@
+package svg
+
+import (
+	"math"
+	"testing"
+
+	"go-readme-stats/app/stats"
+)
+
+func TestHSLRoundTrip(t *testing.T) {
+	for _, colour := range []string{"#000000", "#FFFFFF", "#808080", "#00ADD8", "#B07219", "#F1E05A", "#663399", "#FF0000"} {
+		if result := mustParseHex(colour).toHSL().toRGB().hex(); result != colour {
+			t.Errorf("%s round trip = %s", colour, result)
+		}
+	}
+}
+
+func TestEnsureContrast_ProblemLanguages(t *testing.T) {
+	dark := mustParseHex(registry()["dark"].Background)
+	light := mustParseHex(registry()["light"].Background)
+
+	tests := []struct {
+		name       string
+		colour     string
+		background rgb
+	}{
+		{"Lua on dark", "#000080", dark},
+		{"Unity3D Asset on dark", "#222c37", dark},
+		{"Markdown on dark", "#083fa1", dark},
+		{"JavaScript on light", "#f1e05a", light},
+		{"Shell on light", "#89e051", light},
+		{"Roff on light", "#ecdebe", light},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := mustParseHex(tt.colour)
+			result := ensureContrast(tt.colour, []rgb{tt.background}, DefaultMinContrast)
+			adjusted := mustParseHex(result)
+
+			if ratio := contrastRatio(adjusted, tt.background); ratio < DefaultMinContrast {
+				t.Errorf("ensureContrast(%s) = %s with contrast %.2f, want at least %g", tt.colour, result, ratio, DefaultMinContrast)
+			}
+			if math.Abs(adjusted.toHSL().H-original.toHSL().H) > 2 {
+				t.Errorf("ensureContrast(%s) = %s changed the hue", tt.colour, result)
+			}
+		})
+	}
+}
+
+func TestEnsureContrast_Unchanged(t *testing.T) {
+	dark := mustParseHex(registry()["dark"].Background)
+
+	for _, colour := range []string{"#00ADD8", "#dea584", "not a colour"} {
+		if result := ensureContrast(colour, []rgb{dark}, DefaultMinContrast); result != colour {
+			t.Errorf("ensureContrast(%s) = %s, want unchanged", colour, result)
+		}
+	}
+}
+
+func TestEnsureContrast_KeepsAlpha(t *testing.T) {
+	result := ensureContrast("#00008080", []rgb{mustParseHex("#0D1117")}, DefaultMinContrast)
+
+	if len(result) != 9 || result[7:] != "80" {
+		t.Errorf("ensureContrast() = %s, want the alpha channel kept", result)
+	}
+}
+
+func TestEnsureContrast_BothPalettes(t *testing.T) {
+	backgrounds := []rgb{mustParseHex("#FFFFFF"), mustParseHex("#0D1117")}
+	result := mustParseHex(ensureContrast("#f1e05a", backgrounds, DefaultMinContrast))
+
+	if ratio := worstContrast(result, backgrounds); ratio < DefaultMinContrast {
+		t.Errorf("worst contrast = %.2f, want at least %g against both backgrounds", ratio, DefaultMinContrast)
+	}
+}
+
+func TestApplyContrast(t *testing.T) {
+	languages := []stats.Lang{
+		{Name: "Go", Colour: "#00ADD8"},
+		{Name: "Lua", Colour: "#000080"},
+	}
+
+	adjusted := applyContrast(languages, []string{"#0D1117"}, DefaultMinContrast)
+
+	if adjusted[0].Colour != "#00ADD8" {
+		t.Errorf("Go colour = %s, want unchanged", adjusted[0].Colour)
+	}
+	if adjusted[1].Colour == "#000080" {
+		t.Error("Lua colour wasn't adjusted")
+	}
+	if languages[1].Colour != "#000080" {
+		t.Error("applyContrast() modified the input slice")
+	}
+
+	if disabled := applyContrast(languages, []string{"#0D1117"}, 0); disabled[1].Colour != "#000080" {
+		t.Errorf("Lua colour = %s with min_contrast=0, want unchanged", disabled[1].Colour)
+	}
+}
+
+func TestPrepare_ContrastKeepsChosenColours(t *testing.T) {
+	languages := []stats.Lang{
+		{Name: "Lua", Colour: "#000080"},
+		{Name: "D", Colour: "#BA595E"},
+		{Name: "Other (2)", Colour: "#000080", Others: []string{"C", "Zig"}},
+	}
+
+	opts := DefaultOptions()
+	opts.LangColours = map[string]string{"D": "000000"}
+	opts.Colours.Other = "#111111"
+	data := prepare(opts, languages)
+
+	if data.Languages[0].Colour == "#000080" {
+		t.Error("linguist colour for Lua wasn't adjusted")
+	}
+	if data.Languages[1].Colour != "#000000" {
+		t.Errorf("D colour = %s, want the chosen #000000", data.Languages[1].Colour)
+	}
+	if data.Languages[2].Colour != "#111111" {
+		t.Errorf("Other colour = %s, want the chosen #111111", data.Languages[2].Colour)
+	}
+
+	opts = DefaultOptions()
+	opts.Theme = "light"
+	opts.Palette = "okabe-ito"
+	data = prepare(opts, []stats.Lang{{Name: "Go"}, {Name: "Lua"}, {Name: "C"}, {Name: "D"}})
+
+	if data.Languages[3].Colour != "#F0E442" {
+		t.Errorf("palette colour = %s, want the unchanged #F0E442", data.Languages[3].Colour)
+	}
+}
@@ -0,0 +1,30 @@
+package svg
+
+import (
+	"log"
+	"sync"
+)
+
+// embedded is data built into the binary and parsed on first use. A file that fails to load is logged
+// and replaced by the fallback, so it degrades the output rather than failing requests.
+type embedded[T any] struct {
+	name     string
+	load     func() (T, error)
+	fallback T
+
+	once  sync.Once
+	value T
+}
+
+func (e *embedded[T]) get() T {
+	e.once.Do(func() {
+		value, err := e.load()
+		if err != nil {
+			log.Printf("Warning: Failed to load embedded %s, using fallback: %v", e.name, err)
+			value = e.fallback
+		}
+		e.value = value
+	})
+
+	return e.value
+}
@@ -0,0 +1,51 @@
+package svg
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEmbedded_Get(t *testing.T) {
+	calls := 0
+	e := embedded[int]{name: "numbers", load: func() (int, error) {
+		calls++
+		return 42, nil
+	}, fallback: -1}
+
+	if e.get() != 42 || e.get() != 42 {
+		t.Errorf("get() = %d, want 42", e.get())
+	}
+	if calls != 1 {
+		t.Errorf("load called %d times, want once", calls)
+	}
+}
+
+func TestEmbedded_Fallback(t *testing.T) {
+	e := embedded[int]{name: "numbers", load: func() (int, error) {
+		return 0, errors.New("corrupt")
+	}, fallback: -1}
+
+	if value := e.get(); value != -1 {
+		t.Errorf("get() = %d, want the fallback -1", value)
+	}
+}
+
+func TestEmbeddedFallbacks(t *testing.T) {
+	if _, err := decodeLocales([]byte(`{"de": {"header": "Sprachen"}}`)); err == nil {
+		t.Error("decodeLocales() expected error without the default locale")
+	}
+
+	if _, exists := locales.fallback[DefaultLocale]; !exists {
+		t.Errorf("locale fallback is missing %q", DefaultLocale)
+	}
+
+	for _, name := range []string{DefaultTheme, autoLightTheme, autoDarkTheme} {
+		if _, exists := themes.fallback[name]; !exists {
+			t.Errorf("theme fallback is missing %q", name)
+		}
+	}
+
+	if _, err := fallbackTheme.normalise(); err != nil {
+		t.Errorf("fallback theme is invalid: %v", err)
+	}
+}
@@ -2,68 +2,329 @@ package svg
 
 import (
 	"bytes"
-	_ "embed"
+	"embed"
 	"fmt"
 	"html/template"
+	"strconv"
+	"strings"
 
 	"go-readme-stats/app/stats"
 )
 
 const (
-	baseHeight   = 114.5
-	heightStep   = 20.0
-	templateName = "template.svg"
+	DefaultLayout = "default"
+
+	animationDuration = 600 // Milliseconds each element takes to grow or fade in
+	animationStep     = 150 // Milliseconds between consecutive elements starting their animation
 )
 
-//go:embed template.svg
-var templateContent string
+//go:embed *.svg
+var templateFiles embed.FS
+
+// layouts maps each supported layout to its template, geometry and raster drawing.
+var layouts = map[string]struct {
+	template     string
+	compute      func([]stats.Lang, Options) Layout
+	draw         func(*canvas, SVGData)
+	smallCorners bool // Halve the theme's corner radius, for strips much smaller than a card
+}{
+	"default": {"template.svg", computeStackedLayout, drawStacked, false},
+	"donut":   {"donut.svg", computeDonutLayout, drawDonut, false},
+	"pie":     {"pie.svg", computePieLayout, drawPie, false},
+	"bars":    {"bars.svg", computeBarsLayout, drawBars, false},
+	"compact": {"compact.svg", computeCompactLayout, drawCompact, true},
+}
+
+// Options controls how the SVG is rendered.
+type Options struct {
+	Layout       string
+	Theme        string
+	Header       string
+	Value        string            // Legend value: percent, bytes or lines
+	Columns      int               // Number of legend columns
+	RowSpacing   float64           // Vertical distance between legend rows
+	PieLabels    bool              // Draw values inside pie slices that are big enough
+	InlineLabels int               // Number of languages labelled in the compact layout
+	Colours      Theme             // Overrides layered on top of the theme, empty fields keep the theme's colours
+	Palette      string            // Colour-blind-safe palette replacing the linguist colours, empty keeps them
+	LangColours  map[string]string // Per-language colour overrides keyed by language name
+	MinContrast  float64           // Minimum contrast of linguist colours against the background, zero disables the adjustment
+	AutoWidth    bool              // Grow the card to fit the header and legend instead of truncating them
+	Locale       string            // Language tag for number formatting and right-to-left mirroring, e.g. "de" or "he"
+	Width        float64           // Card width, must stay DefaultWidth for the compact layout which sizes itself to its labels
+	BarHeight    *float64          // Overrides the theme's bar height, nil keeps it
+	BorderRadius *float64          // Overrides the theme's corner radius, nil keeps it
+	HideBorder   bool              // Draw the card without its border
+	Scale        float64           // Multiplies the rendered size without changing the layout, e.g. 2 for HiDPI
+	Animate      bool              // Grow bars and fade in legend entries one after another
+	Icons        bool              // Draw language logos instead of legend dots where one exists
+}
+
+// DefaultOptions returns the options used when a request doesn't override them.
+func DefaultOptions() Options {
+	return Options{
+		Layout:       DefaultLayout,
+		Theme:        DefaultTheme,
+		Header:       "Languages",
+		Value:        "percent",
+		Columns:      DefaultColumns,
+		RowSpacing:   DefaultRowSpacing,
+		InlineLabels: DefaultInlineLabels,
+		MinContrast:  DefaultMinContrast,
+		Locale:       DefaultLocale,
+		Width:        DefaultWidth,
+		Scale:        DefaultScale,
+	}
+}
+
+// Validate checks that the options contain supported values.
+func (o Options) Validate() error {
+	if _, exists := layouts[o.Layout]; !exists {
+		return fmt.Errorf("unknown layout %q", o.Layout)
+	}
+
+	switch o.Value {
+	case "percent", "bytes", "lines":
+	default:
+		return fmt.Errorf("value must be one of percent, bytes or lines, got %q", o.Value)
+	}
+
+	if o.Columns < MinColumns || o.Columns > MaxColumns {
+		return fmt.Errorf("columns must be between %d and %d", MinColumns, MaxColumns)
+	}
+
+	if !(o.RowSpacing >= MinRowSpacing && o.RowSpacing <= MaxRowSpacing) {
+		return fmt.Errorf("row_spacing must be between %g and %g", MinRowSpacing, MaxRowSpacing)
+	}
+
+	if o.InlineLabels < 0 || o.InlineLabels > MaxInlineLabels {
+		return fmt.Errorf("inline_labels must be between 0 and %d", MaxInlineLabels)
+	}
+
+	if _, exists := lookupLocale(o.Locale); !exists {
+		return fmt.Errorf("locale must be one of %s, got %q", localeNames(), o.Locale)
+	}
+
+	if !(o.Width >= MinWidth && o.Width <= MaxWidth) {
+		return fmt.Errorf("width must be between %g and %g", MinWidth, MaxWidth)
+	}
+
+	// The compact strip sizes itself to its labels and never has a border, so hide_border is accepted
+	// but changes nothing there
+	if o.Layout == "compact" && o.Width != DefaultWidth {
+		return fmt.Errorf("width doesn't apply to the compact layout, which sizes itself to its labels")
+	}
+
+	if o.BarHeight != nil && !(*o.BarHeight >= minBarHeight && *o.BarHeight <= maxBarHeight) {
+		return fmt.Errorf("bar_height must be between %g and %g", minBarHeight, maxBarHeight)
+	}
+
+	if o.BorderRadius != nil && !(*o.BorderRadius >= 0 && *o.BorderRadius <= maxCornerRadius) {
+		return fmt.Errorf("border_radius must be between 0 and %g", maxCornerRadius)
+	}
+
+	if !(o.Scale >= MinScale && o.Scale <= MaxScale) {
+		return fmt.Errorf("scale must be between %g and %g", MinScale, MaxScale)
+	}
+
+	if !(o.MinContrast >= 0 && o.MinContrast <= MaxMinContrast) {
+		return fmt.Errorf("min_contrast must be between 0 and %g", MaxMinContrast)
+	}
+
+	if _, exists := palettes[o.Palette]; o.Palette != "" && !exists {
+		return fmt.Errorf("palette must be one of %s, got %q", paletteNames(), o.Palette)
+	}
+
+	for name, colour := range o.LangColours {
+		if _, err := ParseColour(colour); err != nil {
+			return fmt.Errorf("colors: %s: %w", name, err)
+		}
+	}
+
+	for _, override := range []struct{ name, colour string }{
+		{"bg_color", o.Colours.Background},
+		{"border_color", o.Colours.Border},
+		{"text_color", o.Colours.Text},
+		{"secondary_text_color", o.Colours.SecondaryText},
+		{"other_color", o.Colours.Other},
+	} {
+		if override.colour == "" {
+			continue
+		}
+
+		if _, err := ParseColour(override.colour); err != nil {
+			return fmt.Errorf("%s: %w", override.name, err)
+		}
+	}
+
+	return nil
+}
 
 type SVGData struct {
-	Theme         Theme
-	Height        float64
-	Header        string
-	Languages     []stats.Lang // Includes colour codes
-	LanguageCount int
+	Template    string
+	Theme       Theme
+	Layout      Layout
+	Header      string
+	Value       string
+	Locale      Locale
+	Languages   []stats.Lang // Includes colour codes
+	Title       string       // Accessible name, the header or a generic one when it's empty
+	Description string       // Accessible summary of every entry, e.g. "Go 45.5%, Java 30.2%"
+
+	Animate           bool
+	AnimationDuration int    // Milliseconds
+	Icons             []Icon // Icons used by the legend, defined once
 }
 
 // Generate creates an SVG of language statistics.
-func Generate(theme, header string, languages []stats.Lang) (string, error) {
-	languageCount := len(languages)
+func Generate(opts Options, languages []stats.Lang) (string, error) {
+	return generateSVG(prepare(opts, languages))
+}
+
+// prepare resolves the theme and colours and computes the layout, giving everything either renderer draws.
+func prepare(opts Options, languages []stats.Lang) SVGData {
+	layout, exists := layouts[opts.Layout]
+	if !exists {
+		layout = layouts[DefaultLayout]
+	}
+
+	theme := GetTheme(opts.Theme).withOverrides(opts.Colours)
+	if layout.smallCorners {
+		theme.CornerRadius /= 2 // An explicit border_radius below is kept as given
+	}
+	theme = theme.withDimensions(opts.BarHeight, opts.BorderRadius)
+	opts.BarHeight, opts.BorderRadius = &theme.BarHeight, &theme.CornerRadius // Layouts read the resolved dimensions
+	// Only linguist's colours are adjusted; palettes, colors= and the Other colour were chosen on purpose
+	languages = applyContrast(languages, theme.backgrounds(), opts.MinContrast)
+	languages = applyLangColours(languages, palettes[opts.Palette], opts.LangColours)
+	languages = applyOtherColour(languages, theme.Other)
+
+	locale := GetLocale(opts.Locale)
+	title := opts.Header
+	if title == "" {
+		title = locale.Header
+	}
+
+	computed := layout.compute(languages, opts)
+	computed.Scale = opts.Scale
+
+	var icons []Icon
+	if opts.Icons {
+		icons = attachIcons(computed.Legend, iconTable())
+	}
 
 	data := SVGData{
-		Theme:         GetTheme(theme),
-		Height:        calculateSVGHeight(languageCount),
-		Header:        header,
-		Languages:     languages,
-		LanguageCount: languageCount,
+		Template:    layout.template,
+		Theme:       theme,
+		Layout:      computed,
+		Header:      opts.Header,
+		Value:       opts.Value,
+		Locale:      locale,
+		Languages:   languages,
+		Title:       title,
+		Description: describe(languages, opts.Value, locale),
+		Animate:     opts.Animate,
+		Icons:       icons,
+	}
+	if opts.Animate {
+		data.AnimationDuration = animationDuration
 	}
 
-	return generateSVG(data)
+	return data
 }
 
-// calculateSVGHeight returns SVG height adjusted for language count.
-func calculateSVGHeight(languageCount int) float64 {
-	steps := (languageCount + 1) / 2
-	return baseHeight + float64(steps-1)*heightStep
+func generateSVG(data SVGData) (string, error) {
+	return executeTemplate(data.Template, data.Value, data.Locale, data)
 }
 
-func generateSVG(data SVGData) (string, error) {
-	tmpl, err := template.New(templateName).Funcs(template.FuncMap{
+// executeTemplate renders the named template along with the shared partials, formatting legend
+// values with the given value and locale.
+func executeTemplate(name, value string, locale Locale, data any) (string, error) {
+	tmpl, err := template.New(name).Funcs(template.FuncMap{
 		"sumPrev": sumPreviousPercent,
-	}).Parse(templateContent)
+		"css":     func(s string) template.CSS { return template.CSS(s) }, // Only for values validated when the theme was loaded
+		"value": func(lang stats.Lang) string {
+			return formatValue(lang, value, locale)
+		},
+		"delay": animationDelay,
+		"tooltip": func(lang stats.Lang) string {
+			return formatTooltip(lang, value, locale)
+		},
+	}).ParseFS(templateFiles, name, "partials.svg")
 
 	if err != nil {
 		return "", fmt.Errorf("failed to parse template: %w", err)
 	}
 
 	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
+	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
 		return "", fmt.Errorf("failed to execute template: %w", err)
 	}
 
 	return buf.String(), nil
 }
 
+// format returns the legend text for a language with the options' value and locale.
+func (o Options) format(lang stats.Lang) string {
+	return formatValue(lang, o.Value, GetLocale(o.Locale))
+}
+
+// rtl reports whether the options' locale is written right to left.
+func (o Options) rtl() bool {
+	return GetLocale(o.Locale).RTL
+}
+
+// formatLabel returns the language name followed by its legend value, e.g. "Go 45.5%".
+func formatLabel(lang stats.Lang, value string, locale Locale) string {
+	return lang.Name + " " + formatValue(lang, value, locale)
+}
+
+// formatTooltip returns the label followed by the language's size and reach, e.g.
+// "Go 45.5% — 1.2 MB across 14 repos", leaving out what the label already shows or what wasn't fetched.
+func formatTooltip(lang stats.Lang, value string, locale Locale) string {
+	label := formatLabel(lang, value, locale)
+	if lang.RepoCount == 0 {
+		return label
+	}
+
+	repos := stats.FormatRepos(lang.RepoCount)
+	switch {
+	case value == "bytes":
+		return label + " across " + repos
+	case lang.Bytes > 0:
+		return label + " — " + locale.number(stats.FormatBytes(lang.Bytes)) + " across " + repos
+	default: // Primary mode doesn't fetch byte counts
+		return label + " — " + repos
+	}
+}
+
+// describe summarises the languages for screen readers, e.g. "Go 45.5%, Java 30.2%".
+func describe(languages []stats.Lang, value string, locale Locale) string {
+	labels := make([]string, len(languages))
+	for i, lang := range languages {
+		labels[i] = formatLabel(lang, value, locale)
+	}
+
+	return strings.Join(labels, ", ")
+}
+
+// formatValue returns the legend text for a language according to the chosen value, using the locale's number format.
+func formatValue(lang stats.Lang, value string, locale Locale) string {
+	switch value {
+	case "bytes":
+		return locale.number(stats.FormatBytes(lang.Bytes))
+	case "lines":
+		return locale.number(stats.FormatLines(lang.Lines))
+	default:
+		return locale.percent(lang.Percent)
+	}
+}
+
+// animationDelay returns the CSS delay of the element at idx, so elements animate one after another.
+func animationDelay(idx int) template.CSS {
+	return template.CSS(strconv.Itoa(idx*animationStep) + "ms")
+}
+
 // sumPreviousPercent calculates cumulative percentage for stacked progress bars.
 func sumPreviousPercent(languages []stats.Lang, idx int) float64 {
 	sum := 0.0
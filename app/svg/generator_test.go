@@ -2,33 +2,10 @@ package svg
 
 import (
 	"go-readme-stats/app/stats"
+	"strings"
 	"testing"
 )
 
-func TestCalculateSVGHeight(t *testing.T) {
-	tests := []struct {
-		name           string
-		languageCount  int
-		expectedHeight float64
-	}{
-		{"One language", 1, 114.5},
-		{"Two languages", 2, 114.5},
-		{"Three languages", 3, 134.5},
-		{"Four languages", 4, 134.5},
-		{"Five languages", 5, 154.5},
-		{"Six languages", 6, 154.5},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := calculateSVGHeight(tt.languageCount)
-			if result != tt.expectedHeight {
-				t.Errorf("calculateSVGHeight(%d) = %v, want %v", tt.languageCount, result, tt.expectedHeight)
-			}
-		})
-	}
-}
-
 func TestSumPreviousPercent(t *testing.T) {
 	languages := []stats.Lang{
 		{Name: "Go", Percent: 45.5},
@@ -57,3 +34,166 @@ func TestSumPreviousPercent(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatValue(t *testing.T) {
+	lang := stats.Lang{Name: "Go", Percent: 45.5, Bytes: 1_258_291, Lines: 12_400}
+
+	tests := []struct {
+		value    string
+		locale   string
+		expected string
+	}{
+		{"percent", "en", "45.5%"},
+		{"bytes", "en", "1.2 MB"},
+		{"lines", "en", "≈12.4k lines"},
+		{"percent", "de", "45,5\u00A0%"},
+		{"percent", "fr", "45,5\u202F%"},
+		{"percent", "tr", "%45,5"},
+		{"percent", "ar", "45٫5٪"},
+		{"bytes", "de", "1,2 MB"},
+		{"lines", "fr", "≈12,4k lines"},
+	}
+
+	for _, tt := range tests {
+		if result := formatValue(lang, tt.value, GetLocale(tt.locale)); result != tt.expected {
+			t.Errorf("formatValue(%s, %s) = %q, want %q", tt.value, tt.locale, result, tt.expected)
+		}
+	}
+}
+
+func TestFormatTooltip(t *testing.T) {
+	tests := []struct {
+		name     string
+		lang     stats.Lang
+		value    string
+		expected string
+	}{
+		{"Percent", stats.Lang{Name: "Go", Percent: 45.5, Bytes: 1_258_291, RepoCount: 14}, "percent", "Go 45.5% — 1.2 MB across 14 repos"},
+		{"Bytes", stats.Lang{Name: "Go", Percent: 45.5, Bytes: 1_258_291, RepoCount: 14}, "bytes", "Go 1.2 MB across 14 repos"},
+		{"Single repo", stats.Lang{Name: "Lua", Percent: 2, Bytes: 900, RepoCount: 1}, "percent", "Lua 2% — 900 B across 1 repo"},
+		{"Primary mode", stats.Lang{Name: "Go", Percent: 50, RepoCount: 3}, "percent", "Go 50% — 3 repos"},
+		{"No repo count", stats.Lang{Name: "Go", Percent: 45.5}, "percent", "Go 45.5%"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := formatTooltip(tt.lang, tt.value, GetLocale(DefaultLocale)); result != tt.expected {
+				t.Errorf("formatTooltip() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	languages := []stats.Lang{
+		{Name: "Go", Percent: 45.5, Lines: 12400},
+		{Name: "Java", Percent: 30.2, Lines: 1},
+	}
+
+	if result := describe(languages, "percent", GetLocale(DefaultLocale)); result != "Go 45.5%, Java 30.2%" {
+		t.Errorf("describe(percent) = %q", result)
+	}
+
+	if result := describe(languages, "lines", GetLocale(DefaultLocale)); result != "Go ≈12.4k lines, Java ≈1 line" {
+		t.Errorf("describe(lines) = %q", result)
+	}
+}
+
+func TestGenerate_Accessibility(t *testing.T) {
+	languages := []stats.Lang{{Name: "<Go>", Percent: 100, Colour: "#00ADD8"}}
+
+	for name := range layouts {
+		t.Run(name, func(t *testing.T) {
+			opts := DefaultOptions()
+			opts.Layout = name
+			opts.Header = ""
+
+			result, err := Generate(opts, languages)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			for _, want := range []string{
+				`role="img" aria-labelledby="card-title card-desc"`,
+				`<title id="card-title">Languages</title>`,
+				`<desc id="card-desc">&lt;Go&gt; 100%</desc>`,
+				`<title>&lt;Go&gt; 100%</title>`,
+			} {
+				if !strings.Contains(result, want) {
+					t.Errorf("output is missing %s", want)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerate_AnimationsOnlyWhenEnabled(t *testing.T) {
+	for name := range layouts {
+		t.Run(name, func(t *testing.T) {
+			opts := DefaultOptions()
+			opts.Layout = name
+
+			static, err := Generate(opts, goldenLanguages)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			if strings.Contains(static, "@keyframes") || strings.Contains(static, "animation-delay") {
+				t.Error("default output contains animations")
+			}
+
+			opts.Animate = true
+			animated, err := Generate(opts, goldenLanguages)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			for _, want := range []string{"@keyframes", "prefers-reduced-motion: reduce", "animation-delay: 0ms", "animation-delay: 150ms"} {
+				if !strings.Contains(animated, want) {
+					t.Errorf("animated output is missing %q", want)
+				}
+			}
+		})
+	}
+}
+
+func TestOptionsValidate(t *testing.T) {
+	if err := DefaultOptions().Validate(); err != nil {
+		t.Errorf("DefaultOptions().Validate() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*Options)
+	}{
+		{"Unknown layout", func(o *Options) { o.Layout = "radar" }},
+		{"Unknown value", func(o *Options) { o.Value = "words" }},
+		{"Too few columns", func(o *Options) { o.Columns = 0 }},
+		{"Too many columns", func(o *Options) { o.Columns = MaxColumns + 1 }},
+		{"Row spacing too small", func(o *Options) { o.RowSpacing = MinRowSpacing - 1 }},
+		{"Row spacing too large", func(o *Options) { o.RowSpacing = MaxRowSpacing + 1 }},
+		{"Too many inline labels", func(o *Options) { o.InlineLabels = MaxInlineLabels + 1 }},
+		{"Invalid background colour", func(o *Options) { o.Colours.Background = "red;}" }},
+		{"Invalid other colour", func(o *Options) { o.Colours.Other = "url(#x)" }},
+		{"Unknown palette", func(o *Options) { o.Palette = "rainbow" }},
+		{"Unknown locale", func(o *Options) { o.Locale = "xx" }},
+		{"Width too small", func(o *Options) { o.Width = MinWidth - 1 }},
+		{"Width too large", func(o *Options) { o.Width = MaxWidth + 1 }},
+		{"Width with compact layout", func(o *Options) { o.Layout = "compact"; o.Width = 400 }},
+		{"Bar height too large", func(o *Options) { height := maxBarHeight + 1; o.BarHeight = &height }},
+		{"Negative border radius", func(o *Options) { radius := -1.0; o.BorderRadius = &radius }},
+		{"Scale too small", func(o *Options) { o.Scale = MinScale / 2 }},
+		{"Scale too large", func(o *Options) { o.Scale = MaxScale + 1 }},
+		{"Negative min contrast", func(o *Options) { o.MinContrast = -1 }},
+		{"Min contrast too large", func(o *Options) { o.MinContrast = MaxMinContrast + 1 }},
+		{"Invalid language colour", func(o *Options) { o.LangColours = map[string]string{"Go": "blue;}"} }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions()
+			tt.modify(&opts)
+			if err := opts.Validate(); err == nil {
+				t.Error("Validate() expected error")
+			}
+		})
+	}
+}
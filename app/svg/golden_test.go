@@ -0,0 +1,147 @@
+package svg
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go-readme-stats/app/stats"
+)
+
+var update = flag.Bool("update", false, "update golden files in testdata")
+
+var goldenLanguages = []stats.Lang{
+	{Name: "Go", Percent: 45.5, Colour: "#00ADD8"},
+	{Name: "Java", Percent: 30.2, Colour: "#b07219"},
+	{Name: "JavaScript", Percent: 15.8, Colour: "#f1e05a"},
+	{Name: "Python", Percent: 8.5, Colour: "#3572A5"},
+}
+
+// assertGolden compares the generated SVG with testdata/<name>.golden.
+// Run `go test ./app/svg -update` to regenerate the files after an intended change.
+func assertGolden(t *testing.T, name string, opts Options, languages []stats.Lang) {
+	t.Helper()
+
+	result, err := Generate(opts, languages)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	compareGolden(t, name, result)
+}
+
+// compareGolden compares an SVG with testdata/<name>.golden, rewriting the file with -update.
+func compareGolden(t *testing.T, name, result string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll("testdata", 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(result), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+
+	if result != string(expected) {
+		t.Errorf("output differs from %s, run with -update if the change is intended\ngot:\n%s", path, result)
+	}
+}
+
+func TestGenerate_Golden(t *testing.T) {
+	tests := []struct {
+		name      string
+		layout    string
+		pieLabels bool
+		languages []stats.Lang
+	}{
+		{"default", "default", false, goldenLanguages},
+		{"donut", "donut", false, goldenLanguages},
+		{"donut_single", "donut", false, []stats.Lang{{Name: "Go", Percent: 100, Colour: "#00ADD8"}}},
+		{"donut_small_slices", "donut", false, []stats.Lang{
+			{Name: "Go", Percent: 99.7, Colour: "#00ADD8"},
+			{Name: "Shell", Percent: 0.2, Colour: "#89e051"},
+			{Name: "Makefile", Percent: 0.1, Colour: "#427819"},
+		}},
+		{"donut_half", "donut", false, []stats.Lang{
+			{Name: "Go", Percent: 50, Colour: "#00ADD8"},
+			{Name: "Rust", Percent: 50, Colour: "#dea584"},
+		}},
+		{"pie", "pie", false, goldenLanguages},
+		{"pie_labels", "pie", true, goldenLanguages},
+		{"pie_labels_single", "pie", true, []stats.Lang{{Name: "Go", Percent: 100, Colour: "#00ADD8"}}},
+		{"bars", "bars", false, goldenLanguages},
+		{"compact", "compact", false, goldenLanguages},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions()
+			opts.Layout = tt.layout
+			opts.PieLabels = tt.pieLabels
+			assertGolden(t, tt.name, opts, tt.languages)
+		})
+	}
+}
+
+func TestGenerate_GoldenAutoTheme(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Theme = AutoTheme
+	assertGolden(t, "auto_theme", opts, goldenLanguages)
+}
+
+func TestGenerate_GoldenLocales(t *testing.T) {
+	tests := []struct {
+		name      string
+		layout    string
+		locale    string
+		pieLabels bool
+	}{
+		{"default_de", "default", "de", false},
+		{"default_he", "default", "he", false},
+		{"pie_labels_ar", "pie", "ar", true},
+		{"bars_he", "bars", "he", false},
+		{"compact_ar", "compact", "ar", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions()
+			opts.Layout = tt.layout
+			opts.Locale = tt.locale
+			opts.Header = GetLocale(tt.locale).Header
+			opts.PieLabels = tt.pieLabels
+			assertGolden(t, tt.name, opts, goldenLanguages)
+		})
+	}
+}
+
+func TestGenerate_GoldenDimensions(t *testing.T) {
+	barHeight, borderRadius := 12.0, 0.0
+
+	opts := DefaultOptions()
+	opts.Width = 400
+	opts.BarHeight = &barHeight
+	opts.BorderRadius = &borderRadius
+	opts.HideBorder = true
+	opts.Scale = 2
+	assertGolden(t, "dimensions", opts, goldenLanguages)
+}
+
+func TestGenerate_GoldenAnimated(t *testing.T) {
+	for _, layout := range []string{"default", "donut", "bars", "compact"} {
+		t.Run(layout, func(t *testing.T) {
+			opts := DefaultOptions()
+			opts.Layout = layout
+			opts.Animate = true
+			assertGolden(t, layout+"_animated", opts, goldenLanguages)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package svg
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// icons.json holds Simple Icons (CC0) paths on a 24x24 grid keyed by linguist name,
+// regenerated with `go run ./cmd/fetchicons`.
+//
+//go:embed icons.json
+var iconsJSON []byte
+
+var icons = embedded[map[string]string]{
+	name:     "icons",
+	load:     func() (map[string]string, error) { return decodeIcons(iconsJSON) },
+	fallback: map[string]string{}, // Every legend entry keeps its dot
+}
+
+// Icon is a language logo inlined once into the card's <defs>.
+type Icon struct {
+	ID   string
+	Path string
+}
+
+// iconTable returns the embedded icon paths keyed by language name.
+func iconTable() map[string]string {
+	return icons.get()
+}
+
+func decodeIcons(data []byte) (map[string]string, error) {
+	var table map[string]string
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("failed to decode icons: %w", err)
+	}
+
+	return table, nil
+}
+
+// attachIcons points each legend entry with an icon in table at its definition, and returns the
+// definitions of the icons used. Entries without an icon keep the dot.
+func attachIcons(legend []LegendItem, table map[string]string) []Icon {
+	var used []Icon
+	ids := make(map[string]string)
+
+	for i := range legend {
+		name := legend[i].Lang.Name
+		path, exists := table[name]
+		if !exists {
+			continue
+		}
+
+		id, defined := ids[name]
+		if !defined {
+			id = "icon-" + strconv.Itoa(len(used))
+			ids[name] = id
+			used = append(used, Icon{ID: id, Path: path})
+		}
+		legend[i].Icon = id
+	}
+
+	return used
+}
@@ -0,0 +1,77 @@
+package svg
+
+import (
+	"strings"
+	"testing"
+
+	"go-readme-stats/app/stats"
+)
+
+func TestAttachIcons(t *testing.T) {
+	legend := []LegendItem{
+		{Lang: stats.Lang{Name: "Go"}},
+		{Lang: stats.Lang{Name: "Brainfuck"}},
+		{Lang: stats.Lang{Name: "Rust"}},
+		{Lang: stats.Lang{Name: "Go"}},
+	}
+	table := map[string]string{"Go": "M0 0h24v24H0z", "Rust": "M12 0L24 24H0z", "Zig": "M0 0z"}
+
+	used := attachIcons(legend, table)
+
+	expectedIDs := []string{"icon-0", "", "icon-1", "icon-0"}
+	for i, item := range legend {
+		if item.Icon != expectedIDs[i] {
+			t.Errorf("[%d] %s icon = %q, want %q", i, item.Lang.Name, item.Icon, expectedIDs[i])
+		}
+	}
+
+	if len(used) != 2 || used[0].Path != table["Go"] || used[1].Path != table["Rust"] {
+		t.Errorf("used icons = %+v, want Go and Rust defined once each", used)
+	}
+}
+
+func TestEmbeddedIcons(t *testing.T) {
+	if len(iconTable()) == 0 {
+		t.Fatal("icons.json is empty, run `go run ./cmd/fetchicons` to populate it")
+	}
+
+	for _, name := range []string{"Go", "Rust", "Python"} {
+		if _, exists := iconTable()[name]; !exists {
+			t.Errorf("no embedded icon for %q", name)
+		}
+	}
+
+	for name, path := range iconTable() {
+		if path == "" || strings.ContainsAny(path, `"<>`) {
+			t.Errorf("icon for %q has an invalid path %q", name, path)
+		}
+	}
+}
+
+func TestGenerate_Icons(t *testing.T) {
+	original := iconTable() // Loads the embedded icons before they're replaced
+	icons.value = map[string]string{"Go": "M0 0h24v24H0z"}
+	defer func() { icons.value = original }()
+
+	opts := DefaultOptions()
+	opts.Icons = true
+	result, err := Generate(opts, goldenLanguages)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if count := strings.Count(result, "<symbol "); count != 1 {
+		t.Errorf("output defines %d icons, want 1", count)
+	}
+	if !strings.Contains(result, `<use href="#icon-0" x="0" y="1" width="10" height="10" fill="#00ADD8"/>`) {
+		t.Error("Go legend entry doesn't use its icon")
+	}
+	if count := strings.Count(result, `<use href="#legend-dot"`); count != len(goldenLanguages)-1 {
+		t.Errorf("output has %d dots, want %d for languages without icons", count, len(goldenLanguages)-1)
+	}
+
+	opts.Icons = false
+	if result, _ := Generate(opts, goldenLanguages); strings.Contains(result, "<symbol ") {
+		t.Error("output defines icons when they're disabled")
+	}
+}
@@ -0,0 +1,486 @@
+package svg
+
+import (
+	"fmt"
+	"math"
+
+	"go-readme-stats/app/stats"
+)
+
+const (
+	paddingX     = 24.0
+	borderWidth  = 2.0
+	headerY      = 36.0 // Baseline of the header text
+	barY         = 48.0 // Top of the stacked bar
+	legendGap    = 17.0 // Space between the bottom of the stacked bar and the first legend row
+	bottomMargin = 41.5 // Space below the top of the last legend row
+	columnGap    = 4.0  // Horizontal space between legend columns
+	legendTextX  = 18.0 // Offset of the legend text from its dot
+	legendTextY  = 10.5 // Baseline of the legend text within a row
+	legendDot    = 10.0 // Width of the legend dot
+
+	headerFontSize    = 16.0
+	minHeaderFontSize = 12.0 // Long headers shrink down to this size before being truncated
+	legendFontSize    = 12.0
+
+	chartTop       = 52.0 // Top of circular charts
+	chartRadius    = 50.0
+	donutThickness = 16.0
+	chartGap       = 32.0 // Horizontal space between a chart and its legend
+	segmentGap     = 1.5  // Stroke separating adjacent segments
+	chartMargin    = 24.0 // Space below a chart or its legend
+
+	rowsTop    = 52.0 // Top of the first row in the bars layout
+	rowTextY   = 10.0 // Baseline of a row's name and value
+	rowBarY    = 18.0 // Offset of a row's bar below the top of the row
+	rowGap     = 10.0 // Space between a row's bar and the next row
+	rowsMargin = 16.0 // Space below the last row
+	rowTextGap = 8.0  // Minimum space between a row's name and value
+
+	compactHeight    = 20.0
+	compactPadding   = 6.0 // Space at either end of the strip
+	compactBarWidth  = 80.0
+	compactGap       = 8.0 // Space between the bar and labels, and between labels
+	compactFontSize  = 11.0
+	compactDot       = 7.0  // Diameter of a label's dot
+	compactDotGap    = 4.0  // Space between a dot and its text
+	compactNameWidth = 72.0 // Names wider than this are truncated
+	compactMaxWidth  = 480.0
+	compactTextY     = 14.0 // Baseline of the label text
+
+	DefaultColumns    = 2
+	MinColumns        = 1
+	MaxColumns        = 3
+	DefaultRowSpacing = 20.0
+	MinRowSpacing     = 14.0
+	MaxRowSpacing     = 40.0
+
+	DefaultInlineLabels = 3
+	MaxInlineLabels     = 6
+
+	DefaultWidth = 344.0
+	MinWidth     = 250.0
+	MaxWidth     = 800.0 // Also caps opts.AutoWidth, longer text is still truncated
+	DefaultScale = 1.0
+	MinScale     = 0.5
+	MaxScale     = 4.0
+)
+
+var (
+	headerFont      = font{Size: headerFontSize, Bold: true}
+	legendNameFont  = font{Size: legendFontSize, Bold: true}
+	legendValueFont = font{Size: legendFontSize}
+	compactNameFont = font{Size: compactFontSize, Bold: true}
+	compactFont     = font{Size: compactFontSize}
+	sliceLabelFont  = font{Size: 10, Bold: true}
+)
+
+// Layout holds the computed geometry of the card, so the template contains no hard-coded positions.
+type Layout struct {
+	Width    float64
+	Height   float64
+	Scale    float64 // Rendered size relative to Width and Height, zero means 1
+	PaddingX float64
+
+	CornerRadius float64 // Corner radius of the card
+	BorderWidth  float64 // Zero when the border is hidden
+	BarRadius    float64 // Corner radius of the bars
+
+	Header     string // Shrunk or truncated to fit the card
+	HeaderSize float64
+	HeaderX    float64
+	HeaderY    float64
+	BarY       float64
+	BarWidth   float64
+	BarHeight  float64
+	DotX       float64 // Offset of the legend dot from the start of its entry
+	TextX      float64 // Offset of legend text from the start of its entry, or of row names in the bars layout
+	TextY      float64 // Baseline of legend text within its entry
+	ValueX     float64 // End of row values in the bars layout
+	Legend     []LegendItem
+
+	RTL  bool   // Text runs right to left and entries are anchored at their right edge
+	Flip string // Transform mirroring chart geometry in right-to-left layouts, empty otherwise
+
+	Segments   []Segment // Circular chart segments, empty for bar layouts
+	SegmentGap float64
+
+	Rows []Row // Per-language progress bars, only used by the bars layout
+
+	Labels   []InlineLabel // Inline labels, only used by the compact layout
+	TextSize float64
+}
+
+// InlineLabel is a language label placed after the bar in the compact layout.
+type InlineLabel struct {
+	DotX  float64 // Centre of the dot
+	DotY  float64
+	TextX float64
+	Name  string // Truncated to fit
+	Value string
+	Lang  stats.Lang
+}
+
+// Row is a single language's progress bar in the bars layout.
+type Row struct {
+	Y        float64 // Top of the row
+	TextY    float64 // Baseline of the name and value
+	BarY     float64
+	BarX     float64 // Non-zero when right-to-left bars grow from the right edge
+	BarWidth float64 // Scaled to the language's share of the full width
+	Name     string  // Truncated to leave room for the value
+	Lang     stats.Lang
+}
+
+// LegendItem is a single legend entry positioned relative to the content area.
+type LegendItem struct {
+	X    float64
+	Y    float64
+	Name string // Truncated to fit the column
+	Icon string // ID of the language's icon definition, empty to draw the dot
+	Lang stats.Lang
+}
+
+// cardWidthFor returns opts.Width or, with opts.AutoWidth, the wider width that fits both the
+// header and contentWidth, capped at MaxWidth.
+func cardWidthFor(opts Options, contentWidth float64) float64 {
+	width := opts.Width
+	if width <= 0 {
+		width = DefaultWidth
+	}
+
+	if !opts.AutoWidth {
+		return width
+	}
+
+	needed := math.Max(contentWidth, headerFont.width(opts.Header)) + 2*paddingX
+	return math.Min(math.Max(math.Ceil(needed), width), MaxWidth)
+}
+
+// newCard returns a layout of the given width with the card's padding, header position, corners and border set.
+func newCard(opts Options, width float64) Layout {
+	layout := Layout{
+		Width:        width,
+		PaddingX:     paddingX,
+		HeaderY:      headerY,
+		CornerRadius: opts.cornerRadius(),
+		BorderWidth:  borderWidth,
+	}
+
+	if opts.HideBorder {
+		layout.BorderWidth = 0
+	}
+
+	layout.Header, layout.HeaderSize = fitHeader(opts.Header, width-2*paddingX)
+
+	return layout
+}
+
+// barHeight returns the bar height chosen by the options, or the default one.
+func (o Options) barHeight() float64 {
+	if o.BarHeight != nil {
+		return *o.BarHeight
+	}
+
+	return defaultBarHeight
+}
+
+// cornerRadius returns the card corner radius chosen by the options, or the default one.
+func (o Options) cornerRadius() float64 {
+	if o.BorderRadius != nil {
+		return *o.BorderRadius
+	}
+
+	return defaultCornerRadius
+}
+
+// ScaledWidth returns the rendered width of the card.
+func (l Layout) ScaledWidth() float64 {
+	return round2(l.Width * l.scale())
+}
+
+// ScaledHeight returns the rendered height of the card.
+func (l Layout) ScaledHeight() float64 {
+	return round2(l.Height * l.scale())
+}
+
+func (l Layout) scale() float64 {
+	if l.Scale <= 0 {
+		return 1
+	}
+
+	return l.Scale
+}
+
+// fitHeader shrinks the header font until the text fits maxWidth, truncating it once minHeaderFontSize is reached.
+func fitHeader(header string, maxWidth float64) (string, float64) {
+	for size := headerFontSize; size > minHeaderFontSize; size-- {
+		if (font{Size: size, Bold: true}).width(header) <= maxWidth {
+			return header, size
+		}
+	}
+
+	return font{Size: minHeaderFontSize, Bold: true}.truncate(header, maxWidth), minHeaderFontSize
+}
+
+// legendWidth returns the width of a legend entry from the start of its dot to the end of its value.
+func legendWidth(name, value string) float64 {
+	return legendTextX + legendNameFont.width(name) + legendValueFont.width(" "+value)
+}
+
+// widestLegendEntry returns the width of the longest legend entry before truncation.
+func widestLegendEntry(languages []stats.Lang, opts Options) float64 {
+	widest := 0.0
+	for _, lang := range languages {
+		widest = math.Max(widest, legendWidth(lang.Name, opts.format(lang)))
+	}
+
+	return widest
+}
+
+// fitLegendName truncates name so its legend entry fits within maxWidth, keeping the value whole.
+func fitLegendName(name, value string, maxWidth float64) string {
+	return legendNameFont.truncate(name, maxWidth-legendWidth("", value))
+}
+
+// computeStackedLayout places a stacked bar under the header and arranges the legend into rows
+// of opts.Columns entries, filled left to right, sizing the card to fit them.
+func computeStackedLayout(languages []stats.Lang, opts Options) Layout {
+	columns := opts.Columns
+	if columns < MinColumns {
+		columns = DefaultColumns
+	}
+
+	rowSpacing := opts.RowSpacing
+	if rowSpacing <= 0 {
+		rowSpacing = DefaultRowSpacing
+	}
+
+	legendSpan := float64(columns)*(widestLegendEntry(languages, opts)+columnGap) - columnGap
+	width := cardWidthFor(opts, legendSpan)
+	contentWidth := width - 2*paddingX
+	columnWidth := (contentWidth + columnGap) / float64(columns)
+
+	barHeight := opts.barHeight()
+	legendTop := barY + barHeight + legendGap
+
+	layout := newCard(opts, width)
+	layout.BarY = barY
+	layout.BarWidth = contentWidth
+	layout.BarHeight = barHeight
+	layout.BarRadius = barHeight / 2
+	layout.TextX = legendTextX
+	layout.TextY = legendTextY
+	layout.Legend = make([]LegendItem, len(languages))
+
+	for i, lang := range languages {
+		layout.Legend[i] = LegendItem{
+			X:    float64(i%columns) * columnWidth,
+			Y:    legendTop + float64(i/columns)*rowSpacing,
+			Name: fitLegendName(lang.Name, opts.format(lang), columnWidth-columnGap),
+			Lang: lang,
+		}
+	}
+
+	rows := int(math.Ceil(float64(len(languages)) / float64(columns)))
+	layout.Height = legendTop + float64(max(rows-1, 0))*rowSpacing + bottomMargin
+
+	if opts.rtl() {
+		mirrorContent(&layout)
+	}
+
+	return layout
+}
+
+// computeBarsLayout gives each language its own row with a name, value and a bar scaled to its share,
+// sizing the card to fit the rows.
+func computeBarsLayout(languages []stats.Lang, opts Options) Layout {
+	widest := 0.0
+	for _, lang := range languages {
+		widest = math.Max(widest, legendNameFont.width(lang.Name)+rowTextGap+legendValueFont.width(opts.format(lang)))
+	}
+
+	width := cardWidthFor(opts, widest)
+	contentWidth := width - 2*paddingX
+
+	barHeight := opts.barHeight()
+	rowHeight := rowBarY + barHeight + rowGap
+
+	layout := newCard(opts, width)
+	layout.BarWidth = contentWidth
+	layout.BarHeight = barHeight
+	layout.BarRadius = barHeight / 2
+	layout.ValueX = contentWidth
+	layout.Rows = make([]Row, len(languages))
+
+	for i, lang := range languages {
+		y := rowsTop + float64(i)*rowHeight
+		nameWidth := contentWidth - rowTextGap - legendValueFont.width(opts.format(lang))
+		layout.Rows[i] = Row{
+			Y:        y,
+			TextY:    y + rowTextY,
+			BarY:     y + rowBarY,
+			BarWidth: round2(contentWidth * math.Min(math.Max(lang.Percent, 0), 100) / 100),
+			Name:     legendNameFont.truncate(lang.Name, nameWidth),
+			Lang:     lang,
+		}
+	}
+
+	lastRow := rowsTop + float64(max(len(languages)-1, 0))*rowHeight
+	layout.Height = lastRow + rowBarY + barHeight + rowsMargin
+
+	if opts.rtl() {
+		mirrorContent(&layout)
+		layout.TextX, layout.ValueX = contentWidth, 0
+		for i := range layout.Rows {
+			layout.Rows[i].BarX = round2(contentWidth - layout.Rows[i].BarWidth)
+		}
+	}
+
+	return layout
+}
+
+// computeCompactLayout places a short stacked bar in a single strip followed by labels for the top
+// opts.InlineLabels languages. Long names are truncated, and labels that would make the strip wider
+// than compactMaxWidth are dropped.
+func computeCompactLayout(languages []stats.Lang, opts Options) Layout {
+	barHeight := opts.barHeight()
+
+	layout := Layout{
+		Height:       compactHeight,
+		PaddingX:     compactPadding,
+		CornerRadius: opts.cornerRadius(),
+		BarY:         (compactHeight - barHeight) / 2,
+		BarWidth:     compactBarWidth,
+		BarHeight:    barHeight,
+		BarRadius:    barHeight / 2,
+		TextY:        compactTextY,
+		TextSize:     compactFontSize,
+	}
+
+	x := compactPadding + compactBarWidth
+	for i, lang := range languages {
+		if i >= opts.InlineLabels {
+			break
+		}
+
+		name := compactNameFont.truncate(lang.Name, compactNameWidth)
+		value := opts.format(lang)
+		width := compactDot + compactDotGap + compactNameFont.width(name) + compactFont.width(" "+value)
+
+		if x+compactGap+width+compactPadding > compactMaxWidth {
+			break
+		}
+
+		layout.Labels = append(layout.Labels, InlineLabel{
+			DotX:  round2(x + compactGap + compactDot/2),
+			DotY:  compactHeight / 2,
+			TextX: round2(x + compactGap + compactDot + compactDotGap),
+			Name:  name,
+			Value: value,
+			Lang:  lang,
+		})
+		x += compactGap + width
+	}
+
+	layout.Width = math.Ceil(x + compactPadding)
+
+	if opts.rtl() {
+		mirrorCompact(&layout)
+	}
+
+	return layout
+}
+
+// computeDonutLayout places a ring chart under the header with a single-column legend beside it.
+func computeDonutLayout(languages []stats.Lang, opts Options) Layout {
+	layout := computeCircularLayout(languages, opts, chartRadius-donutThickness)
+	if opts.rtl() {
+		mirrorContent(&layout)
+	}
+
+	return layout
+}
+
+// computePieLayout places a pie chart under the header with a single-column legend beside it,
+// adding in-slice labels when opts.PieLabels is set.
+func computePieLayout(languages []stats.Lang, opts Options) Layout {
+	layout := computeCircularLayout(languages, opts, 0)
+	if opts.PieLabels {
+		placeLabels(layout.Segments, chartRadius, chartTop+chartRadius, chartRadius, opts)
+	}
+	if opts.rtl() {
+		mirrorContent(&layout)
+	}
+
+	return layout
+}
+
+// computeCircularLayout places a circular chart with the given inner radius under the header,
+// with a single-column legend beside it, sizing the card to fit whichever is taller.
+func computeCircularLayout(languages []stats.Lang, opts Options, innerRadius float64) Layout {
+	rowSpacing := opts.RowSpacing
+	if rowSpacing <= 0 {
+		rowSpacing = DefaultRowSpacing
+	}
+
+	legendX := 2*chartRadius + chartGap
+	width := cardWidthFor(opts, legendX+widestLegendEntry(languages, opts))
+	contentWidth := width - 2*paddingX
+
+	layout := newCard(opts, width)
+	layout.TextX = legendTextX
+	layout.TextY = legendTextY
+	layout.Legend = make([]LegendItem, len(languages))
+	layout.Segments = computeSegments(languages, chartRadius, chartTop+chartRadius, chartRadius, innerRadius)
+
+	if len(layout.Segments) > 1 {
+		layout.SegmentGap = segmentGap
+	}
+
+	for i, lang := range languages {
+		layout.Legend[i] = LegendItem{
+			X:    legendX,
+			Y:    chartTop + float64(i)*rowSpacing,
+			Name: fitLegendName(lang.Name, opts.format(lang), contentWidth-legendX),
+			Lang: lang,
+		}
+	}
+
+	chartBottom := chartTop + 2*chartRadius
+	legendBottom := chartTop + float64(len(languages))*rowSpacing
+	layout.Height = math.Max(chartBottom, legendBottom) + chartMargin
+
+	return layout
+}
+
+// mirrorContent flips a layout drawn in the padded content area for right-to-left locales.
+// Text keeps its reading direction and is anchored at its right edge, while Flip mirrors the chart geometry.
+func mirrorContent(l *Layout) {
+	contentWidth := l.Width - 2*l.PaddingX
+
+	l.RTL = true
+	l.Flip = fmt.Sprintf("translate(%s, 0) scale(-1, 1)", num(contentWidth))
+	l.HeaderX = contentWidth
+	l.DotX = -legendDot
+	l.TextX = -l.TextX
+
+	for i := range l.Legend {
+		l.Legend[i].X = round2(contentWidth - l.Legend[i].X)
+	}
+	for i := range l.Segments {
+		l.Segments[i].LabelX = round2(contentWidth - l.Segments[i].LabelX)
+	}
+}
+
+// mirrorCompact flips the compact strip for right-to-left locales, so the bar sits at the right
+// and labels follow it leftwards.
+func mirrorCompact(l *Layout) {
+	l.RTL = true
+	l.Flip = fmt.Sprintf("translate(%s, 0) scale(-1, 1)", num(l.Width))
+
+	for i := range l.Labels {
+		l.Labels[i].DotX = round2(l.Width - l.Labels[i].DotX)
+		l.Labels[i].TextX = round2(l.Width - l.Labels[i].TextX)
+	}
+}
@@ -0,0 +1,398 @@
+package svg
+
+import (
+	"go-readme-stats/app/stats"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestComputeStackedLayout_Height(t *testing.T) {
+	tests := []struct {
+		name           string
+		languageCount  int
+		columns        int
+		rowSpacing     float64
+		expectedHeight float64
+	}{
+		{"No languages", 0, 2, 20, 114.5},
+		{"One language", 1, 2, 20, 114.5},
+		{"Two languages", 2, 2, 20, 114.5},
+		{"Three languages", 3, 2, 20, 134.5},
+		{"Six languages", 6, 2, 20, 154.5},
+		{"Twenty languages", 20, 2, 20, 294.5},
+		{"Single column", 3, 1, 20, 154.5},
+		{"Three columns", 7, 3, 20, 154.5},
+		{"Wider row spacing", 6, 2, 30, 174.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			languages := make([]stats.Lang, tt.languageCount)
+			layout := computeStackedLayout(languages, Options{Columns: tt.columns, RowSpacing: tt.rowSpacing})
+
+			if layout.Height != tt.expectedHeight {
+				t.Errorf("Height = %v, want %v", layout.Height, tt.expectedHeight)
+			}
+		})
+	}
+}
+
+func TestComputeStackedLayout_LegendPositions(t *testing.T) {
+	tests := []struct {
+		name     string
+		columns  int
+		expected [][2]float64
+	}{
+		{"Single column", 1, [][2]float64{{0, 73}, {0, 93}, {0, 113}, {0, 133}}},
+		{"Two columns", 2, [][2]float64{{0, 73}, {150, 73}, {0, 93}, {150, 93}}},
+		{"Three columns", 3, [][2]float64{{0, 73}, {100, 73}, {200, 73}, {0, 93}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			languages := make([]stats.Lang, len(tt.expected))
+			layout := computeStackedLayout(languages, Options{Columns: tt.columns, RowSpacing: DefaultRowSpacing})
+
+			if len(layout.Legend) != len(tt.expected) {
+				t.Fatalf("got %d legend items, want %d", len(layout.Legend), len(tt.expected))
+			}
+
+			for i, item := range layout.Legend {
+				if item.X != tt.expected[i][0] || item.Y != tt.expected[i][1] {
+					t.Errorf("[%d] position = (%v, %v), want (%v, %v)", i, item.X, item.Y, tt.expected[i][0], tt.expected[i][1])
+				}
+			}
+		})
+	}
+}
+
+func TestComputeStackedLayout_LongNames(t *testing.T) {
+	languages := []stats.Lang{
+		{Name: "Jupyter Notebook", Percent: 40.5},
+		{Name: "Other (27)", Percent: 30.25},
+		{Name: "Go", Percent: 29.25},
+	}
+	opts := Options{Columns: 2, RowSpacing: DefaultRowSpacing, Value: "percent", Header: "Languages"}
+
+	layout := computeStackedLayout(languages, opts)
+
+	columnWidth := (DefaultWidth - 2*paddingX + columnGap) / 2
+	for i, item := range layout.Legend {
+		if width := legendWidth(item.Name, opts.format(item.Lang)); width > columnWidth-columnGap {
+			t.Errorf("[%d] %q is %v wide, overflowing the %v column", i, item.Name, width, columnWidth-columnGap)
+		}
+	}
+	if layout.Legend[0].Name == languages[0].Name {
+		t.Errorf("long name %q wasn't truncated", layout.Legend[0].Name)
+	}
+	if layout.Legend[2].Name != "Go" {
+		t.Errorf("short name = %q, want unchanged", layout.Legend[2].Name)
+	}
+
+	opts.AutoWidth = true
+	auto := computeStackedLayout(languages, opts)
+
+	if auto.Width <= DefaultWidth || auto.Width > MaxWidth {
+		t.Errorf("auto Width = %v, want between %v and %v", auto.Width, DefaultWidth, MaxWidth)
+	}
+	for i, item := range auto.Legend {
+		if item.Name != languages[i].Name {
+			t.Errorf("[%d] auto width name = %q, want %q", i, item.Name, languages[i].Name)
+		}
+	}
+}
+
+func TestComputeLayouts_AutoWidth(t *testing.T) {
+	short := []stats.Lang{{Name: "Go", Percent: 100}}
+	long := []stats.Lang{{Name: strings.Repeat("Very Long Language Name ", 10), Percent: 100}}
+
+	for name, layout := range layouts {
+		if name == "compact" {
+			continue
+		}
+
+		t.Run(name, func(t *testing.T) {
+			opts := DefaultOptions()
+			opts.AutoWidth = true
+
+			if width := layout.compute(short, opts).Width; width != DefaultWidth {
+				t.Errorf("Width = %v for a short legend, want the default %v", width, DefaultWidth)
+			}
+			if width := layout.compute(long, opts).Width; width != MaxWidth {
+				t.Errorf("Width = %v for a long legend, want the cap %v", width, MaxWidth)
+			}
+
+			opts.Header = strings.Repeat("Header ", 20)
+			result := layout.compute(short, opts)
+			if result.Width != MaxWidth || result.HeaderSize != minHeaderFontSize || !strings.HasSuffix(result.Header, ellipsis) {
+				t.Errorf("long header gave Width %v and %q at %vpx, want it capped and truncated", result.Width, result.Header, result.HeaderSize)
+			}
+		})
+	}
+}
+
+func TestComputeDonutLayout(t *testing.T) {
+	tests := []struct {
+		name           string
+		languageCount  int
+		expectedHeight float64
+		expectedGap    float64
+	}{
+		{"Single language has no gap", 1, 176, 0},
+		{"Legend shorter than chart", 4, 176, 1.5},
+		{"Legend taller than chart", 10, 276, 1.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			languages := make([]stats.Lang, tt.languageCount)
+			for i := range languages {
+				languages[i].Percent = 100 / float64(tt.languageCount)
+			}
+
+			layout := computeDonutLayout(languages, Options{RowSpacing: DefaultRowSpacing})
+
+			if layout.Height != tt.expectedHeight {
+				t.Errorf("Height = %v, want %v", layout.Height, tt.expectedHeight)
+			}
+
+			if layout.SegmentGap != tt.expectedGap {
+				t.Errorf("SegmentGap = %v, want %v", layout.SegmentGap, tt.expectedGap)
+			}
+
+			if len(layout.Segments) != tt.languageCount {
+				t.Errorf("got %d segments, want %d", len(layout.Segments), tt.languageCount)
+			}
+
+			for i, item := range layout.Legend {
+				if item.X != 132 {
+					t.Errorf("[%d] legend x = %v, want 132", i, item.X)
+				}
+			}
+		})
+	}
+}
+
+func TestComputeBarsLayout(t *testing.T) {
+	languages := []stats.Lang{
+		{Name: "Go", Percent: 50},
+		{Name: "Rust", Percent: 37.5},
+		{Name: "Shell", Percent: 12.5},
+	}
+
+	layout := computeBarsLayout(languages, DefaultOptions())
+
+	expected := []Row{
+		{Y: 52, TextY: 62, BarY: 70, BarWidth: 148},
+		{Y: 88, TextY: 98, BarY: 106, BarWidth: 111},
+		{Y: 124, TextY: 134, BarY: 142, BarWidth: 37},
+	}
+
+	if len(layout.Rows) != len(expected) {
+		t.Fatalf("got %d rows, want %d", len(layout.Rows), len(expected))
+	}
+
+	for i, row := range layout.Rows {
+		if row.Y != expected[i].Y || row.TextY != expected[i].TextY || row.BarY != expected[i].BarY || row.BarWidth != expected[i].BarWidth {
+			t.Errorf("[%d] row = %+v, want %+v", i, row, expected[i])
+		}
+		if row.Lang.Name != languages[i].Name {
+			t.Errorf("[%d] language = %s, want %s", i, row.Lang.Name, languages[i].Name)
+		}
+	}
+
+	if layout.Height != 166 {
+		t.Errorf("Height = %v, want 166", layout.Height)
+	}
+}
+
+func TestComputeBarsLayout_Height(t *testing.T) {
+	tests := []struct {
+		languageCount  int
+		expectedHeight float64
+	}{
+		{1, 94},
+		{6, 274},
+		{20, 778},
+	}
+
+	for _, tt := range tests {
+		layout := computeBarsLayout(make([]stats.Lang, tt.languageCount), DefaultOptions())
+		if layout.Height != tt.expectedHeight {
+			t.Errorf("Height with %d languages = %v, want %v", tt.languageCount, layout.Height, tt.expectedHeight)
+		}
+	}
+}
+
+func TestComputeCompactLayout(t *testing.T) {
+	languages := []stats.Lang{
+		{Name: "Go", Percent: 45.5},
+		{Name: "Jupyter Notebook", Percent: 30.2},
+		{Name: "JavaScript", Percent: 15.8},
+		{Name: "Python", Percent: 8.5},
+	}
+
+	opts := DefaultOptions()
+	layout := computeCompactLayout(languages, opts)
+
+	if layout.Height != 20 {
+		t.Errorf("Height = %v, want 20", layout.Height)
+	}
+
+	if len(layout.Labels) != opts.InlineLabels {
+		t.Fatalf("got %d labels, want %d", len(layout.Labels), opts.InlineLabels)
+	}
+
+	if name := layout.Labels[1].Name; name != "Jupyter No…" {
+		t.Errorf("long name = %q, want truncated", name)
+	}
+
+	// Labels follow each other after the bar without overlapping
+	previousEnd := compactPadding + compactBarWidth
+	for i, label := range layout.Labels {
+		start := label.DotX - compactDot/2
+		if start < previousEnd {
+			t.Errorf("[%d] label starts at %v, before previous end %v", i, start, previousEnd)
+		}
+		previousEnd = label.TextX + compactNameFont.width(label.Name) + compactFont.width(" "+label.Value)
+	}
+
+	if expected := math.Ceil(previousEnd + compactPadding); layout.Width != expected {
+		t.Errorf("Width = %v, want %v", layout.Width, expected)
+	}
+}
+
+func TestComputeCompactLayout_MaxWidth(t *testing.T) {
+	languages := make([]stats.Lang, MaxInlineLabels)
+	for i := range languages {
+		languages[i] = stats.Lang{Name: "Jupyter Notebook", Percent: 100.0 / MaxInlineLabels}
+	}
+
+	opts := DefaultOptions()
+	opts.InlineLabels = MaxInlineLabels
+	layout := computeCompactLayout(languages, opts)
+
+	if layout.Width > compactMaxWidth {
+		t.Errorf("Width = %v, exceeds %v", layout.Width, compactMaxWidth)
+	}
+
+	if len(layout.Labels) == 0 || len(layout.Labels) == MaxInlineLabels {
+		t.Errorf("got %d labels, want some dropped to fit", len(layout.Labels))
+	}
+}
+
+func TestComputeCompactLayout_NoLabels(t *testing.T) {
+	opts := DefaultOptions()
+	opts.InlineLabels = 0
+	layout := computeCompactLayout([]stats.Lang{{Name: "Go", Percent: 100}}, opts)
+
+	if len(layout.Labels) != 0 || layout.Width != compactBarWidth+2*compactPadding {
+		t.Errorf("got %d labels and width %v, want a bare bar", len(layout.Labels), layout.Width)
+	}
+}
+
+func TestPrepare_CompactCornerRadius(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Layout = "compact"
+
+	if radius := prepare(opts, goldenLanguages).Layout.CornerRadius; radius != GetTheme(DefaultTheme).CornerRadius/2 {
+		t.Errorf("CornerRadius = %v, want half the theme's", radius)
+	}
+
+	explicit := 10.0
+	opts.BorderRadius = &explicit
+	if radius := prepare(opts, goldenLanguages).Layout.CornerRadius; radius != explicit {
+		t.Errorf("CornerRadius = %v, want the explicit %v", radius, explicit)
+	}
+}
+
+func TestComputeLayouts_RTL(t *testing.T) {
+	languages := []stats.Lang{{Name: "Go", Percent: 60}, {Name: "Java", Percent: 40}}
+	contentWidth := DefaultWidth - 2*paddingX
+
+	ltr := computeStackedLayout(languages, Options{Columns: 2, RowSpacing: DefaultRowSpacing, Locale: "en"})
+	rtl := computeStackedLayout(languages, Options{Columns: 2, RowSpacing: DefaultRowSpacing, Locale: "he"})
+
+	if ltr.RTL || ltr.Flip != "" {
+		t.Errorf("left-to-right layout has RTL %v and Flip %q", ltr.RTL, ltr.Flip)
+	}
+	if !rtl.RTL || rtl.Flip != "translate(296, 0) scale(-1, 1)" || rtl.HeaderX != contentWidth {
+		t.Errorf("right-to-left layout has RTL %v, Flip %q and HeaderX %v", rtl.RTL, rtl.Flip, rtl.HeaderX)
+	}
+	for i := range rtl.Legend {
+		if rtl.Legend[i].X != contentWidth-ltr.Legend[i].X || rtl.Legend[i].Y != ltr.Legend[i].Y {
+			t.Errorf("[%d] legend at (%v, %v), want mirrored (%v, %v)", i, rtl.Legend[i].X, rtl.Legend[i].Y, contentWidth-ltr.Legend[i].X, ltr.Legend[i].Y)
+		}
+	}
+	if rtl.DotX != -legendDot || rtl.TextX != -legendTextX {
+		t.Errorf("DotX = %v and TextX = %v, want the dot and text left of the entry's anchor", rtl.DotX, rtl.TextX)
+	}
+
+	bars := computeBarsLayout(languages, Options{Locale: "ar"})
+	if bars.TextX != contentWidth || bars.ValueX != 0 {
+		t.Errorf("bars TextX = %v and ValueX = %v, want names at the right and values at the left", bars.TextX, bars.ValueX)
+	}
+	for i, row := range bars.Rows {
+		if row.BarX+row.BarWidth != contentWidth {
+			t.Errorf("[%d] bar spans %v to %v, want it to end at the right edge", i, row.BarX, row.BarX+row.BarWidth)
+		}
+	}
+
+	compact := computeCompactLayout(languages, Options{InlineLabels: 2, Locale: "ar"})
+	for i, label := range compact.Labels {
+		if label.TextX >= label.DotX {
+			t.Errorf("[%d] compact text at %v, want it left of the dot at %v", i, label.TextX, label.DotX)
+		}
+	}
+}
+
+func TestComputeLayouts_Dimensions(t *testing.T) {
+	languages := []stats.Lang{{Name: "Go", Percent: 60}, {Name: "Java", Percent: 40}, {Name: "C", Percent: 0}}
+	barHeight, borderRadius := 16.0, 0.0
+	opts := Options{Columns: 2, RowSpacing: DefaultRowSpacing, Width: 400, BarHeight: &barHeight, BorderRadius: &borderRadius, HideBorder: true}
+
+	stacked := computeStackedLayout(languages, opts)
+
+	if stacked.Width != 400 || stacked.BarWidth != 400-2*paddingX {
+		t.Errorf("Width = %v and BarWidth = %v, want 400 and %v", stacked.Width, stacked.BarWidth, 400-2*paddingX)
+	}
+	if stacked.Legend[1].X != (400-2*paddingX+columnGap)/2 {
+		t.Errorf("second column at %v, want it recomputed for the wider card", stacked.Legend[1].X)
+	}
+	if stacked.BarHeight != barHeight || stacked.BarRadius != barHeight/2 {
+		t.Errorf("BarHeight = %v and BarRadius = %v, want %v and %v", stacked.BarHeight, stacked.BarRadius, barHeight, barHeight/2)
+	}
+	if top := stacked.Legend[0].Y; top != barY+barHeight+legendGap {
+		t.Errorf("legend top = %v, want it below the taller bar at %v", top, barY+barHeight+legendGap)
+	}
+	if stacked.CornerRadius != 0 || stacked.BorderWidth != 0 {
+		t.Errorf("CornerRadius = %v and BorderWidth = %v, want square corners without a border", stacked.CornerRadius, stacked.BorderWidth)
+	}
+
+	bars := computeBarsLayout(languages, opts)
+	if gap := bars.Rows[1].Y - bars.Rows[0].Y; gap != rowBarY+barHeight+rowGap {
+		t.Errorf("row spacing = %v, want %v for the taller bars", gap, rowBarY+barHeight+rowGap)
+	}
+	if bars.Rows[0].BarWidth != round2((400-2*paddingX)*0.6) {
+		t.Errorf("bar width = %v, want it scaled to the wider card", bars.Rows[0].BarWidth)
+	}
+
+	compact := computeCompactLayout(languages, opts)
+	if compact.BarY != (compactHeight-barHeight)/2 {
+		t.Errorf("compact BarY = %v, want the taller bar centred", compact.BarY)
+	}
+}
+
+func TestLayoutScaledSize(t *testing.T) {
+	layout := Layout{Width: 344, Height: 134.5}
+
+	if layout.ScaledWidth() != 344 || layout.ScaledHeight() != 134.5 {
+		t.Errorf("unscaled size = %vx%v, want 344x134.5", layout.ScaledWidth(), layout.ScaledHeight())
+	}
+
+	layout.Scale = 2
+	if layout.ScaledWidth() != 688 || layout.ScaledHeight() != 269 {
+		t.Errorf("scaled size = %vx%v, want 688x269", layout.ScaledWidth(), layout.ScaledHeight())
+	}
+}
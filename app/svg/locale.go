@@ -0,0 +1,93 @@
+package svg
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultLocale = "en"
+
+	numberPlaceholder = "{}"
+)
+
+//go:embed locales.json
+var localesJSON []byte
+
+// Locale holds the text and number conventions of a language.
+type Locale struct {
+	Header  string `json:"header"`  // Default header text
+	Decimal string `json:"decimal"` // Decimal separator
+	Percent string `json:"percent"` // Percentage pattern, with {} in place of the number
+	RTL     bool   `json:"rtl"`     // Mirror the layout for right-to-left scripts
+}
+
+var locales = embedded[map[string]Locale]{
+	name:     "locales",
+	load:     func() (map[string]Locale, error) { return decodeLocales(localesJSON) },
+	fallback: map[string]Locale{DefaultLocale: {Header: "Languages", Decimal: ".", Percent: numberPlaceholder + "%"}},
+}
+
+// localeTable returns the embedded locales keyed by language tag.
+func localeTable() map[string]Locale {
+	return locales.get()
+}
+
+func decodeLocales(data []byte) (map[string]Locale, error) {
+	var table map[string]Locale
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("failed to decode locales: %w", err)
+	}
+
+	if _, exists := table[DefaultLocale]; !exists {
+		return nil, fmt.Errorf("missing default locale %q", DefaultLocale)
+	}
+
+	return table, nil
+}
+
+// lookupLocale finds a locale by its tag, falling back from a regional tag like "de-AT" to its language.
+func lookupLocale(tag string) (Locale, bool) {
+	tag = strings.ToLower(strings.ReplaceAll(tag, "_", "-"))
+	if locale, exists := localeTable()[tag]; exists {
+		return locale, true
+	}
+
+	language, _, _ := strings.Cut(tag, "-")
+	locale, exists := localeTable()[language]
+	return locale, exists
+}
+
+// GetLocale returns the locale for the given tag, or DefaultLocale if it isn't supported.
+func GetLocale(tag string) Locale {
+	if locale, exists := lookupLocale(tag); exists {
+		return locale
+	}
+
+	return localeTable()[DefaultLocale]
+}
+
+// localeNames returns the supported locale tags in alphabetical order, for error messages.
+func localeNames() string {
+	names := make([]string, 0, len(localeTable()))
+	for name := range localeTable() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return strings.Join(names, ", ")
+}
+
+// number swaps the decimal point in a formatted number for the locale's separator.
+func (l Locale) number(s string) string {
+	return strings.Replace(s, ".", l.Decimal, 1)
+}
+
+// percent formats a percentage with the locale's separator and sign placement, e.g. "45,5 %".
+func (l Locale) percent(value float64) string {
+	return strings.Replace(l.Percent, numberPlaceholder, l.number(strconv.FormatFloat(value, 'f', -1, 64)), 1)
+}
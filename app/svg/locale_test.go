@@ -0,0 +1,51 @@
+package svg
+
+import "testing"
+
+func TestLookupLocale(t *testing.T) {
+	tests := []struct {
+		tag      string
+		expected string
+		exists   bool
+	}{
+		{"en", "Languages", true},
+		{"de", "Sprachen", true},
+		{"DE", "Sprachen", true},
+		{"de-AT", "Sprachen", true},
+		{"pt_BR", "Linguagens", true},
+		{"xx", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			locale, exists := lookupLocale(tt.tag)
+			if exists != tt.exists || locale.Header != tt.expected {
+				t.Errorf("lookupLocale(%q) = %q, %v, want %q, %v", tt.tag, locale.Header, exists, tt.expected, tt.exists)
+			}
+		})
+	}
+}
+
+func TestGetLocale_Fallback(t *testing.T) {
+	if locale := GetLocale("xx"); locale != GetLocale(DefaultLocale) {
+		t.Errorf("GetLocale(xx) = %+v, want the %q locale", locale, DefaultLocale)
+	}
+}
+
+func TestEmbeddedLocales(t *testing.T) {
+	for tag, locale := range localeTable() {
+		if locale.Header == "" || locale.Decimal == "" {
+			t.Errorf("locale %q is missing its header or decimal separator", tag)
+		}
+		if locale.percent(1.5) == locale.Percent {
+			t.Errorf("locale %q percent pattern %q has no %s placeholder", tag, locale.Percent, numberPlaceholder)
+		}
+	}
+
+	for _, tag := range []string{"ar", "he"} {
+		if !GetLocale(tag).RTL {
+			t.Errorf("locale %q isn't right-to-left", tag)
+		}
+	}
+}
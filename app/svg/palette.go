@@ -0,0 +1,78 @@
+package svg
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"go-readme-stats/app/stats"
+)
+
+// palettes are colour-blind-safe colour sequences that replace the linguist colours.
+// Okabe-Ito drops its black, which would vanish on dark themes.
+var palettes = map[string][]string{
+	"okabe-ito": {"#E69F00", "#56B4E9", "#009E73", "#F0E442", "#0072B2", "#D55E00", "#CC79A7"},
+	"tol":       {"#4477AA", "#EE6677", "#228833", "#CCBB44", "#66CCEE", "#AA3377", "#BBBBBB"},
+	"tol-muted": {"#CC6677", "#332288", "#DDCC77", "#117733", "#88CCEE", "#882255", "#44AA99", "#999933", "#AA4499"},
+}
+
+// paletteNames returns the supported palette names in alphabetical order, for error messages.
+func paletteNames() string {
+	names := make([]string, 0, len(palettes))
+	for name := range palettes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return strings.Join(names, ", ")
+}
+
+// ParseLangColours parses per-language overrides in the form "Go:00ADD8,Rust:dea584".
+// Colours are validated later by Options.Validate, so they are returned as given.
+func ParseLangColours(value string) (map[string]string, error) {
+	overrides := make(map[string]string)
+	if value == "" {
+		return overrides, nil
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		name, colour, found := strings.Cut(pair, ":")
+		if !found || name == "" || colour == "" {
+			return nil, fmt.Errorf("colors must be a list of Language:colour pairs, got %q", pair)
+		}
+		overrides[name] = colour
+	}
+
+	return overrides, nil
+}
+
+// applyLangColours recolours languages from the palette by rank, then applies per-language overrides.
+// Palette colours skip the "Other" entry so a language keeps its colour whether or not "Other" is shown.
+// Override names match case-insensitively.
+func applyLangColours(languages []stats.Lang, palette []string, overrides map[string]string) []stats.Lang {
+	if len(palette) == 0 && len(overrides) == 0 {
+		return languages
+	}
+
+	byName := make(map[string]string, len(overrides))
+	for name, colour := range overrides {
+		if parsed, err := ParseColour(colour); err == nil {
+			byName[strings.ToLower(name)] = parsed
+		}
+	}
+
+	recoloured := make([]stats.Lang, len(languages))
+	rank := 0
+	for i, lang := range languages {
+		if len(palette) > 0 && !lang.IsOther() {
+			lang.Colour = palette[rank%len(palette)]
+			rank++
+		}
+		if colour, exists := byName[strings.ToLower(lang.Name)]; exists {
+			lang.Colour = colour
+		}
+		recoloured[i] = lang
+	}
+
+	return recoloured
+}
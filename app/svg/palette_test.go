@@ -0,0 +1,106 @@
+package svg
+
+import (
+	"reflect"
+	"testing"
+
+	"go-readme-stats/app/stats"
+)
+
+func TestParseLangColours(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected map[string]string
+		wantErr  bool
+	}{
+		{"Empty", "", map[string]string{}, false},
+		{"Pairs", "Go:00ADD8,Rust:dea584", map[string]string{"Go": "00ADD8", "Rust": "dea584"}, false},
+		{"Hash and names", "C++:#f34b7d,Java:orange", map[string]string{"C++": "#f34b7d", "Java": "orange"}, false},
+		{"Missing colour", "Go:", nil, true},
+		{"Missing separator", "Go", nil, true},
+		{"Trailing comma", "Go:00ADD8,", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseLangColours(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLangColours(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("ParseLangColours(%q) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestApplyLangColours_Palette(t *testing.T) {
+	languages := []stats.Lang{
+		{Name: "Go", Colour: "#00ADD8"},
+		{Name: "Java", Colour: "#b07219"},
+		{Name: "Other (2)", Colour: "#F0F6FC", Others: []string{"C", "Lua"}},
+	}
+	palette := []string{"#111111", "#222222"}
+
+	recoloured := applyLangColours(languages, palette, nil)
+
+	expected := []string{"#111111", "#222222", "#F0F6FC"}
+	for i, lang := range recoloured {
+		if lang.Colour != expected[i] {
+			t.Errorf("%s colour = %s, want %s", lang.Name, lang.Colour, expected[i])
+		}
+	}
+
+	if languages[0].Colour != "#00ADD8" {
+		t.Error("applyLangColours() modified the input slice")
+	}
+}
+
+func TestApplyLangColours_StableByRank(t *testing.T) {
+	palette := palettes["okabe-ito"]
+	languages := make([]stats.Lang, len(palette)+2)
+	for i := range languages {
+		languages[i] = stats.Lang{Name: string(rune('A' + i))}
+	}
+
+	first := applyLangColours(languages, palette, nil)
+	second := applyLangColours(languages[:3], palette, nil)
+
+	for i := range second {
+		if first[i].Colour != second[i].Colour {
+			t.Errorf("rank %d colour = %s with %d languages, %s with 3", i, first[i].Colour, len(languages), second[i].Colour)
+		}
+	}
+
+	if first[len(palette)].Colour != palette[0] {
+		t.Errorf("rank %d colour = %s, want the palette to wrap to %s", len(palette), first[len(palette)].Colour, palette[0])
+	}
+}
+
+func TestApplyLangColours_Overrides(t *testing.T) {
+	languages := []stats.Lang{
+		{Name: "Go", Colour: "#00ADD8"},
+		{Name: "Rust", Colour: "#dea584"},
+		{Name: "Kotlin", Colour: "#A97BFF"},
+	}
+
+	recoloured := applyLangColours(languages, palettes["tol"], map[string]string{"rust": "000", "Kotlin": "purple", "Zig": "#f7a41d"})
+
+	expected := []string{palettes["tol"][0], "#000000", "#800080"}
+	for i, lang := range recoloured {
+		if lang.Colour != expected[i] {
+			t.Errorf("%s colour = %s, want %s", lang.Name, lang.Colour, expected[i])
+		}
+	}
+}
+
+func TestPalettesAreValid(t *testing.T) {
+	for name, palette := range palettes {
+		for _, colour := range palette {
+			if parsed, err := ParseColour(colour); err != nil || parsed != colour {
+				t.Errorf("palette %q colour %q is not a normalised hex colour", name, colour)
+			}
+		}
+	}
+}
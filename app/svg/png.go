@@ -0,0 +1,507 @@
+package svg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"strconv"
+	"strings"
+
+	"go-readme-stats/app/stats"
+
+	xfont "golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+const (
+	arcStep      = 3.0   // Degrees covered by each straight edge when flattening arcs
+	dividerShare = 0.006 // Width of the stacked bar's dividers as a share of the bar
+)
+
+// fonts holds the regular and bold Go fonts, both nil when they fail to parse.
+var fonts = embedded[[2]*opentype.Font]{name: "fonts", load: parseFonts}
+
+// ErrUnsupportedText is returned by GeneratePNG for cards with text the embedded fonts can't draw.
+var ErrUnsupportedText = errors.New("text can't be rendered as PNG")
+
+// fontTable returns the embedded regular and bold fonts.
+func fontTable() [2]*opentype.Font {
+	return fonts.get()
+}
+
+func parseFonts() ([2]*opentype.Font, error) {
+	var parsed [2]*opentype.Font
+	for i, data := range [][]byte{goregular.TTF, gobold.TTF} {
+		f, err := opentype.Parse(data)
+		if err != nil {
+			return [2]*opentype.Font{}, fmt.Errorf("failed to parse font: %w", err)
+		}
+		parsed[i] = f
+	}
+
+	return parsed, nil
+}
+
+// missingGlyph returns the first character of s that the embedded fonts have no glyph for.
+func missingGlyph(s string) (rune, bool) {
+	var buf sfnt.Buffer
+	for _, r := range s {
+		for _, f := range fontTable() {
+			if f == nil {
+				continue // GeneratePNG reports the missing fonts
+			}
+			if index, err := f.GlyphIndex(&buf, r); err != nil || index == 0 {
+				return r, true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+// CheckPNGText returns ErrUnsupportedText when GeneratePNG can't draw the header or the locale's number
+// format, which the embedded fonts only cover in Latin, Greek and Cyrillic. Right-to-left locales are
+// refused too, since the rasteriser neither mirrors the layout nor reorders bidi runs. Language names
+// are only known while drawing, so GeneratePNG checks them itself.
+func CheckPNGText(opts Options) error {
+	locale := GetLocale(opts.Locale)
+	if locale.RTL {
+		return fmt.Errorf("%w: locale %q is right-to-left", ErrUnsupportedText, opts.Locale)
+	}
+
+	for _, text := range []string{opts.Header, locale.Decimal, locale.Percent} {
+		if char, missing := missingGlyph(text); missing {
+			return fmt.Errorf("%w: no glyph for %q in %q", ErrUnsupportedText, char, text)
+		}
+	}
+
+	return nil
+}
+
+// GeneratePNG rasterises the same card as Generate, at opts.Scale pixels per unit.
+// PNGs are static, so animations are skipped, the auto theme uses its light palette, and legend
+// entries keep their dots instead of icons. Cards whose locale, header or language names can't be
+// drawn with the embedded Go fonts fail with ErrUnsupportedText.
+//
+// The layout is measured with the same glyph-width table as the SVG, while text is drawn with the Go
+// fonts, whose advances differ by a few percent. Text positions match the SVG exactly, but a
+// truncated name may end slightly short of or past its measured width.
+func GeneratePNG(opts Options, languages []stats.Lang) ([]byte, error) {
+	if fontTable()[0] == nil {
+		return nil, errors.New("embedded fonts are unavailable")
+	}
+
+	if err := CheckPNGText(opts); err != nil {
+		return nil, err
+	}
+
+	layout, exists := layouts[opts.Layout]
+	if !exists {
+		layout = layouts[DefaultLayout]
+	}
+
+	data := prepare(opts, languages)
+	c := newCanvas(data.Layout)
+	defer c.close()
+
+	layout.draw(c, data)
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, c.img); err != nil {
+		return nil, fmt.Errorf("failed to encode PNG: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// point is a position in pixels.
+type point struct {
+	X, Y float64
+}
+
+// transform maps card units to pixels, from an origin in card units.
+type transform struct {
+	X, Y  float64
+	Scale float64
+}
+
+func (t transform) point(x, y float64) point {
+	return point{(t.X + x) * t.Scale, (t.Y + y) * t.Scale}
+}
+
+// arc returns points along a circle between two angles in degrees, clockwise from 12 o'clock.
+func (t transform) arc(cx, cy, r, start, end float64) []point {
+	steps := max(int(math.Ceil(math.Abs(end-start)/arcStep)), 1)
+
+	points := make([]point, 0, steps+1)
+	for i := 0; i <= steps; i++ {
+		x, y := polar(cx, cy, r, start+(end-start)*float64(i)/float64(steps))
+		points = append(points, t.point(x, y))
+	}
+
+	return points
+}
+
+// rect returns the outline of a rectangle with rounded corners, clamping the radius like SVG does.
+func (t transform) rect(x, y, width, height, radius float64) []point {
+	r := math.Max(0, math.Min(radius, math.Min(width, height)/2))
+
+	var points []point
+	points = append(points, t.arc(x+width-r, y+r, r, 0, 90)...)
+	points = append(points, t.arc(x+width-r, y+height-r, r, 90, 180)...)
+	points = append(points, t.arc(x+r, y+height-r, r, 180, 270)...)
+	points = append(points, t.arc(x+r, y+r, r, 270, 360)...)
+
+	return points
+}
+
+// circle returns the outline of a circle.
+func (t transform) circle(cx, cy, r float64) []point {
+	return t.arc(cx, cy, r, 0, fullCircle)
+}
+
+// sector returns the outline of a ring segment between two angles, or a pie slice when inner is zero.
+func (t transform) sector(cx, cy, outer, inner, start, end float64) []point {
+	points := t.arc(cx, cy, outer, start, end)
+	if inner <= 0 {
+		return append(points, t.point(cx, cy))
+	}
+
+	return append(points, reversed(t.arc(cx, cy, inner, start, end))...)
+}
+
+// line returns the outline of a straight line of the given width.
+func (t transform) line(x0, y0, x1, y1, width float64) []point {
+	length := math.Hypot(x1-x0, y1-y0)
+	if length == 0 {
+		return nil
+	}
+
+	nx, ny := -(y1-y0)/length*width/2, (x1-x0)/length*width/2
+	return []point{t.point(x0+nx, y0+ny), t.point(x1+nx, y1+ny), t.point(x1-nx, y1-ny), t.point(x0-nx, y0-ny)}
+}
+
+// reversed returns the points in the opposite order, turning an outline into a hole.
+func reversed(points []point) []point {
+	result := make([]point, len(points))
+	for i, p := range points {
+		result[len(points)-1-i] = p
+	}
+
+	return result
+}
+
+// canvas is the image a card is rasterised onto.
+type canvas struct {
+	img   *image.RGBA
+	scale float64
+	faces map[font]xfont.Face
+	err   error // First text the fonts couldn't draw
+}
+
+func newCanvas(l Layout) *canvas {
+	scale := l.scale()
+	bounds := image.Rect(0, 0, int(math.Ceil(l.Width*scale)), int(math.Ceil(l.Height*scale)))
+
+	return &canvas{
+		img:   image.NewRGBA(bounds),
+		scale: scale,
+		faces: make(map[font]xfont.Face),
+	}
+}
+
+func (c *canvas) close() {
+	for _, face := range c.faces {
+		face.Close()
+	}
+}
+
+// at returns the transform for an origin in card units.
+func (c *canvas) at(x, y float64) transform {
+	return transform{X: x, Y: y, Scale: c.scale}
+}
+
+// coverage rasterises the outlines with the nonzero rule, returning the covered area and its alpha.
+func (c *canvas) coverage(outlines ...[]point) (image.Rectangle, *image.Alpha) {
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, outline := range outlines {
+		for _, p := range outline {
+			minX, minY = math.Min(minX, p.X), math.Min(minY, p.Y)
+			maxX, maxY = math.Max(maxX, p.X), math.Max(maxY, p.Y)
+		}
+	}
+
+	area := image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX)), int(math.Ceil(maxY)))
+	area = area.Intersect(c.img.Bounds())
+	if area.Empty() {
+		return area, nil
+	}
+
+	r := vector.NewRasterizer(area.Dx(), area.Dy())
+	for _, outline := range outlines {
+		if len(outline) < 3 {
+			continue
+		}
+
+		r.MoveTo(float32(outline[0].X)-float32(area.Min.X), float32(outline[0].Y)-float32(area.Min.Y))
+		for _, p := range outline[1:] {
+			r.LineTo(float32(p.X)-float32(area.Min.X), float32(p.Y)-float32(area.Min.Y))
+		}
+		r.ClosePath()
+	}
+
+	mask := image.NewAlpha(image.Rect(0, 0, area.Dx(), area.Dy()))
+	r.Draw(mask, mask.Bounds(), image.Opaque, image.Point{})
+
+	return area, mask
+}
+
+// fill paints the outlines in colour, limited to the clip mask when one is given.
+func (c *canvas) fill(colour string, clip *image.Alpha, outlines ...[]point) {
+	area, mask := c.coverage(outlines...)
+	if mask == nil {
+		return
+	}
+
+	if clip != nil {
+		for y := range area.Dy() {
+			for x := range area.Dx() {
+				covered := uint16(mask.AlphaAt(x, y).A) * uint16(clip.AlphaAt(area.Min.X+x, area.Min.Y+y).A) / 255
+				mask.SetAlpha(x, y, color.Alpha{A: uint8(covered)})
+			}
+		}
+	}
+
+	draw.DrawMask(c.img, area, image.NewUniform(parseRGBA(colour)), image.Point{}, mask, image.Point{}, draw.Over)
+}
+
+// clip returns a mask of the whole image covering the outlines.
+func (c *canvas) clip(outlines ...[]point) *image.Alpha {
+	clip := image.NewAlpha(c.img.Bounds())
+	if area, mask := c.coverage(outlines...); mask != nil {
+		draw.Draw(clip, area, mask, image.Point{}, draw.Src)
+	}
+
+	return clip
+}
+
+// face returns the font face for f at the canvas scale.
+func (c *canvas) face(f font) xfont.Face {
+	if face, exists := c.faces[f]; exists {
+		return face
+	}
+
+	src := fontTable()[0]
+	if f.Bold {
+		src = fontTable()[1]
+	}
+
+	face, err := opentype.NewFace(src, &opentype.FaceOptions{Size: f.Size * c.scale, DPI: 72, Hinting: xfont.HintingNone})
+	if err != nil {
+		panic(fmt.Sprintf("invalid font size %g: %v", f.Size, err)) // Sizes are constants or validated options
+	}
+	c.faces[f] = face
+
+	return face
+}
+
+// run is a piece of text in a single font and colour.
+type run struct {
+	Text   string
+	Font   font
+	Colour string
+}
+
+// text draws runs one after another from a baseline position, anchored at "start", "middle" or "end"
+// like SVG's text-anchor.
+func (c *canvas) text(t transform, x, y float64, anchor string, runs ...run) {
+	width := fixed.Int26_6(0)
+	for _, r := range runs {
+		if char, missing := missingGlyph(r.Text); missing && c.err == nil {
+			c.err = fmt.Errorf("%w: no glyph for %q in %q", ErrUnsupportedText, char, r.Text)
+		}
+		width += xfont.MeasureString(c.face(r.Font), r.Text)
+	}
+
+	p := t.point(x, y)
+	dot := fixed.Point26_6{X: fixed.Int26_6(p.X * 64), Y: fixed.Int26_6(p.Y * 64)}
+	switch anchor {
+	case "middle":
+		dot.X -= width / 2
+	case "end":
+		dot.X -= width
+	}
+
+	for _, r := range runs {
+		d := xfont.Drawer{Dst: c.img, Src: image.NewUniform(parseRGBA(r.Colour)), Face: c.face(r.Font), Dot: dot}
+		d.DrawString(r.Text)
+		dot = d.Dot
+	}
+}
+
+// parseRGBA parses a colour normalised by ParseColour, including its alpha.
+// Colours that can't be parsed, such as a language without one, are transparent.
+func parseRGBA(colour string) color.NRGBA {
+	hex, err := ParseColour(colour)
+	if err != nil {
+		return color.NRGBA{}
+	}
+
+	hex = strings.TrimPrefix(hex, "#")
+	if len(hex) == 6 {
+		hex += "FF"
+	}
+
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.NRGBA{}
+	}
+
+	return color.NRGBA{R: uint8(value >> 24), G: uint8(value >> 16), B: uint8(value >> 8), A: uint8(value)}
+}
+
+// drawCard paints the card background and border, with the stroke centred on the edge and its
+// outer half clipped like the SVG's.
+func drawCard(c *canvas, d SVGData) {
+	l := d.Layout
+	t := c.at(0, 0)
+	c.fill(d.Theme.Background, nil, t.rect(0, 0, l.Width, l.Height, l.CornerRadius))
+
+	if l.BorderWidth > 0 {
+		inset := l.BorderWidth / 2
+		c.fill(d.Theme.Border, nil,
+			t.rect(0, 0, l.Width, l.Height, l.CornerRadius),
+			reversed(t.rect(inset, inset, l.Width-2*inset, l.Height-2*inset, math.Max(l.CornerRadius-inset, 0))))
+	}
+}
+
+// drawHeader paints the header in the content area.
+func drawHeader(c *canvas, d SVGData) {
+	l := d.Layout
+	c.text(c.at(l.PaddingX, l.HeaderY), l.HeaderX, 0, "start", run{l.Header, font{Size: l.HeaderSize, Bold: true}, d.Theme.Text})
+}
+
+// drawLegend paints the legend entries in the content area.
+func drawLegend(c *canvas, d SVGData) {
+	l := d.Layout
+	for _, item := range l.Legend {
+		t := c.at(l.PaddingX+item.X, item.Y)
+		c.fill(item.Lang.Colour, nil, t.circle(l.DotX+legendDot/2, 6, 4.5))
+		c.text(t, l.TextX, l.TextY, "start",
+			run{item.Name, legendNameFont, d.Theme.Text},
+			run{" " + formatValue(item.Lang, d.Value, d.Locale), legendValueFont, d.Theme.SecondaryText})
+	}
+}
+
+// drawStackedBar paints the languages as one bar with rounded ends, split by dividers when dividerWidth,
+// a share of the bar, is set.
+func drawStackedBar(c *canvas, d SVGData, t transform, dividerWidth float64) {
+	l := d.Layout
+	clip := c.clip(t.rect(0, 0, l.BarWidth, l.BarHeight, l.BarRadius))
+
+	for i, lang := range d.Languages {
+		x := sumPreviousPercent(d.Languages, i) / 100 * l.BarWidth
+		c.fill(lang.Colour, clip, t.rect(x, 0, lang.Percent/100*l.BarWidth, l.BarHeight, 0))
+	}
+
+	if dividerWidth <= 0 {
+		return
+	}
+
+	for i := 1; i < len(d.Languages); i++ {
+		x := sumPreviousPercent(d.Languages, i) / 100 * l.BarWidth
+		c.fill(d.Theme.Divider, nil, t.rect(x, 0, dividerWidth*l.BarWidth, l.BarHeight, 0))
+	}
+}
+
+func drawStacked(c *canvas, d SVGData) {
+	drawCard(c, d)
+	drawHeader(c, d)
+	drawStackedBar(c, d, c.at(d.Layout.PaddingX, d.Layout.BarY), dividerShare)
+	drawLegend(c, d)
+}
+
+func drawDonut(c *canvas, d SVGData) {
+	drawCircular(c, d, chartRadius-donutThickness)
+}
+
+func drawPie(c *canvas, d SVGData) {
+	drawCircular(c, d, 0)
+
+	// Shift the baseline so labels are centred on their position, like dominant-baseline="central"
+	metrics := c.face(sliceLabelFont).Metrics()
+	offset := float64(metrics.Ascent-metrics.Descent) / 64 / 2 / c.scale
+
+	t := c.at(d.Layout.PaddingX, 0)
+	for _, segment := range d.Layout.Segments {
+		if segment.ShowLabel {
+			c.text(t, segment.LabelX, segment.LabelY+offset, "middle", run{segment.Label, sliceLabelFont, segment.LabelColour})
+		}
+	}
+}
+
+// drawCircular paints a ring chart, or a pie when inner is zero, with gaps between segments and the legend beside it.
+func drawCircular(c *canvas, d SVGData, inner float64) {
+	drawCard(c, d)
+	drawHeader(c, d)
+
+	l := d.Layout
+	t := c.at(l.PaddingX, 0)
+	cx, cy := chartRadius, chartTop+chartRadius
+
+	for _, segment := range l.Segments {
+		c.fill(segment.Lang.Colour, nil, t.sector(cx, cy, chartRadius, inner, segment.Start, segment.End))
+	}
+
+	if l.SegmentGap > 0 {
+		for _, segment := range l.Segments {
+			x0, y0 := polar(cx, cy, inner, segment.Start)
+			x1, y1 := polar(cx, cy, chartRadius, segment.Start)
+			c.fill(d.Theme.Divider, nil, t.line(x0, y0, x1, y1, l.SegmentGap))
+		}
+	}
+
+	drawLegend(c, d)
+}
+
+func drawBars(c *canvas, d SVGData) {
+	drawCard(c, d)
+	drawHeader(c, d)
+
+	l := d.Layout
+	t := c.at(l.PaddingX, 0)
+	for _, row := range l.Rows {
+		c.text(t, l.TextX, row.TextY, "start", run{row.Name, legendNameFont, d.Theme.Text})
+		c.text(t, l.ValueX, row.TextY, "end", run{formatValue(row.Lang, d.Value, d.Locale), legendValueFont, d.Theme.SecondaryText})
+		c.fill(d.Theme.Border, nil, t.rect(0, row.BarY, l.BarWidth, l.BarHeight, l.BarRadius))
+		c.fill(row.Lang.Colour, nil, t.rect(row.BarX, row.BarY, row.BarWidth, l.BarHeight, l.BarRadius))
+	}
+}
+
+func drawCompact(c *canvas, d SVGData) {
+	drawCard(c, d)
+
+	l := d.Layout
+	drawStackedBar(c, d, c.at(l.PaddingX, l.BarY), 0)
+
+	t := c.at(0, 0)
+	for _, label := range l.Labels {
+		c.fill(label.Lang.Colour, nil, t.circle(label.DotX, label.DotY, compactDot/2))
+		c.text(t, label.TextX, l.TextY, "start",
+			run{label.Name, font{Size: l.TextSize, Bold: true}, d.Theme.Text},
+			run{" " + label.Value, font{Size: l.TextSize}, d.Theme.SecondaryText})
+	}
+}
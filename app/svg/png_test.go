@@ -0,0 +1,145 @@
+package svg
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+// decodePNG renders the languages as a PNG and decodes it.
+func decodePNG(t *testing.T, opts Options) image.Image {
+	t.Helper()
+
+	data, err := GeneratePNG(opts, goldenLanguages)
+	if err != nil {
+		t.Fatalf("GeneratePNG() error = %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("output isn't a valid PNG: %v", err)
+	}
+
+	return img
+}
+
+// assertColour checks the pixel at (x, y) card units is the given opaque colour.
+func assertColour(t *testing.T, img image.Image, scale, x, y float64, expected string) {
+	t.Helper()
+
+	r, g, b, a := img.At(int(x*scale), int(y*scale)).RGBA()
+	got := color.NRGBA{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), uint8(a >> 8)}
+	if want := parseRGBA(expected); got != want {
+		t.Errorf("pixel at (%g, %g) = %v, want %v", x, y, got, want)
+	}
+}
+
+func TestGeneratePNG_Size(t *testing.T) {
+	for name := range layouts {
+		for _, scale := range []float64{1, 2, 0.5} {
+			opts := DefaultOptions()
+			opts.Layout = name
+			opts.Scale = scale
+
+			layout := prepare(opts, goldenLanguages).Layout
+			bounds := decodePNG(t, opts).Bounds()
+
+			if float64(bounds.Dx()) < layout.Width*scale || float64(bounds.Dx()) > layout.Width*scale+1 ||
+				float64(bounds.Dy()) < layout.Height*scale || float64(bounds.Dy()) > layout.Height*scale+1 {
+				t.Errorf("%s at scale %g is %dx%d, want %gx%g", name, scale, bounds.Dx(), bounds.Dy(), layout.Width*scale, layout.Height*scale)
+			}
+		}
+	}
+}
+
+func TestGeneratePNG_Default(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Scale = 2
+	img := decodePNG(t, opts)
+	theme := GetTheme(DefaultTheme)
+
+	assertColour(t, img, 2, 0, 0, "#00000000") // Outside the rounded corner
+	assertColour(t, img, 2, 0.5, 100, theme.Border)
+	assertColour(t, img, 2, 200, 110, theme.Background)
+	assertColour(t, img, 2, paddingX+20, barY+4, "#00ADD8")             // Go starts the bar
+	assertColour(t, img, 2, DefaultWidth-paddingX-8, barY+4, "#3572A5") // Python ends it
+}
+
+func TestCheckPNGText(t *testing.T) {
+	tests := []struct {
+		locale   string
+		header   string
+		expected bool
+	}{
+		{"en", "", true},
+		{"de", "", true},
+		{"pl", "", true},
+		{"tr", "", true},
+		{"ru", "", true},
+		{"ja", "", false},
+		{"zh", "", false},
+		{"ar", "", false},
+		{"he", "", false},
+		{"ja", "Code", true},
+		{"ar", "Code", false},
+		{"en", "使用言語", false},
+	}
+
+	for _, tt := range tests {
+		opts := DefaultOptions()
+		opts.Locale = tt.locale
+		opts.Header = tt.header
+		if tt.header == "" {
+			opts.Header = GetLocale(tt.locale).Header
+		}
+
+		if err := CheckPNGText(opts); (err == nil) != tt.expected {
+			t.Errorf("CheckPNGText(%s, %q) error = %v, want supported %v", tt.locale, opts.Header, err, tt.expected)
+		}
+	}
+}
+
+func TestGeneratePNG_UnsupportedText(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Options)
+	}{
+		{"Japanese locale", func(o *Options) { o.Locale = "ja"; o.Header = GetLocale("ja").Header }},
+		{"Hebrew locale", func(o *Options) { o.Locale = "he" }},
+		{"CJK header", func(o *Options) { o.Header = "使用言語" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions()
+			tt.modify(&opts)
+
+			if _, err := GeneratePNG(opts, goldenLanguages); !errors.Is(err, ErrUnsupportedText) {
+				t.Errorf("GeneratePNG() error = %v, want ErrUnsupportedText", err)
+			}
+		})
+	}
+}
+
+func TestGeneratePNG_Bars(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Layout = "bars"
+	img := decodePNG(t, opts)
+	theme := GetTheme(DefaultTheme)
+
+	// Python's bar covers 8.5% of its row, leaving the track visible after it
+	pythonBar := rowsTop + 3*(rowBarY+defaultBarHeight+rowGap) + rowBarY + defaultBarHeight/2
+	assertColour(t, img, 1, paddingX+10, pythonBar, "#3572A5")
+	assertColour(t, img, 1, paddingX+100, pythonBar, theme.Border)
+}
+
+func TestGeneratePNG_HideBorder(t *testing.T) {
+	opts := DefaultOptions()
+	opts.HideBorder = true
+	img := decodePNG(t, opts)
+
+	assertColour(t, img, 1, 0.5, 100, GetTheme(DefaultTheme).Background)
+}
@@ -0,0 +1,94 @@
+package svg
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	averageCharWidth = 0.56 // Advance of glyphs missing from the tables, as a fraction of the font size
+	wideCharWidth    = 1.0  // Advance of CJK glyphs, which are full-width in every font of the stack
+	ellipsis         = "…"
+)
+
+// font is a size and weight of the card's font stack, used to measure text before it's rendered.
+type font struct {
+	Size float64
+	Bold bool // Weights of 600 and above
+}
+
+// regularWidths and boldWidths hold the advance widths of printable ASCII (32-126) in thousandths of an em.
+// They come from the Helvetica metrics, which Arial shares, and are within a few percent of Segoe UI and Noto Sans.
+var (
+	regularWidths = [95]uint16{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space to /
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556, // 0 to ?
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778, // @ to O
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556, // P to _
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556, // ` to o
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584, // p to ~
+	}
+	boldWidths = [95]uint16{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	}
+)
+
+// symbolWidths covers the non-ASCII glyphs the card renders itself, in thousandths of an em.
+var symbolWidths = map[rune]uint16{
+	'…':      1000,
+	'≈':      584,
+	'·':      278,
+	'\u00A0': 278, // No-break space, used in localised percentages
+	'\u202F': 200, // Narrow no-break space
+}
+
+// glyphWidth returns the advance of r as a fraction of the font size.
+func (f font) glyphWidth(r rune) float64 {
+	switch {
+	case r >= ' ' && r <= '~':
+		if f.Bold {
+			return float64(boldWidths[r-' ']) / 1000
+		}
+		return float64(regularWidths[r-' ']) / 1000
+	case symbolWidths[r] > 0:
+		return float64(symbolWidths[r]) / 1000
+	case unicode.In(r, unicode.Han, unicode.Hangul, unicode.Hiragana, unicode.Katakana):
+		return wideCharWidth
+	default:
+		return averageCharWidth
+	}
+}
+
+// width returns the approximate rendered width of s.
+func (f font) width(s string) float64 {
+	total := 0.0
+	for _, r := range s {
+		total += f.glyphWidth(r)
+	}
+
+	return total * f.Size
+}
+
+// truncate shortens s with an ellipsis so it fits within maxWidth, dropping any space left before it.
+// Strings that already fit are returned unchanged.
+func (f font) truncate(s string, maxWidth float64) string {
+	if f.width(s) <= maxWidth {
+		return s
+	}
+
+	runes := []rune(s)
+	for len(runes) > 0 && f.width(string(runes)+ellipsis) > maxWidth {
+		runes = runes[:len(runes)-1]
+	}
+
+	if len(runes) == 0 {
+		return ""
+	}
+
+	return strings.TrimRight(string(runes), " ") + ellipsis
+}
@@ -0,0 +1,80 @@
+package svg
+
+import "testing"
+
+func TestFontWidth(t *testing.T) {
+	regular := font{Size: 10}
+	bold := font{Size: 10, Bold: true}
+
+	tests := []struct {
+		name     string
+		font     font
+		input    string
+		expected float64
+	}{
+		{"Empty", regular, "", 0},
+		{"Narrow glyphs", regular, "il", 4.44},
+		{"Wide glyphs", regular, "WM", 17.77},
+		{"Bold is wider", bold, "il", 5.56},
+		{"Ellipsis", regular, "…", 10},
+		{"CJK", regular, "日本", 20},
+		{"Fallback", regular, "é", 5.6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := round2(tt.font.width(tt.input)); result != tt.expected {
+				t.Errorf("width(%q) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFontTruncate(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		maxWidth float64
+		expected string
+	}{
+		{"Fits", "Go", 72, "Go"},
+		{"Truncated with ellipsis", "Jupyter Notebook", 72, "Jupyter Not…"},
+		{"Trailing space dropped", "Jupyter Notebook", 54, "Jupyter…"},
+		{"Too narrow for anything", "Go", 5, ""},
+	}
+
+	f := font{Size: 11}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := f.truncate(tt.input, tt.maxWidth)
+			if result != tt.expected {
+				t.Errorf("truncate(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+			if f.width(result) > tt.maxWidth {
+				t.Errorf("truncate(%q) width %v exceeds %v", tt.input, f.width(result), tt.maxWidth)
+			}
+		})
+	}
+}
+
+func TestFitHeader(t *testing.T) {
+	tests := []struct {
+		name         string
+		header       string
+		expected     string
+		expectedSize float64
+	}{
+		{"Fits", "Languages", "Languages", headerFontSize},
+		{"Shrunk", "Most Used Languages Across My Repos", "Most Used Languages Across My Repos", 15},
+		{"Truncated", "Most Used Programming Languages Across All of My Public Repositories", "Most Used Programming Languages Across All o…", minHeaderFontSize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header, size := fitHeader(tt.header, DefaultWidth-2*paddingX)
+			if header != tt.expected || size != tt.expectedSize {
+				t.Errorf("fitHeader(%q) = %q at %vpx, want %q at %vpx", tt.header, header, size, tt.expected, tt.expectedSize)
+			}
+		})
+	}
+}
@@ -1,42 +1,308 @@
 package svg
 
-const DefaultTheme = "dark"
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	DefaultTheme   = "dark"
+	AutoTheme      = "auto" // Follows the viewer's colour scheme, using autoLightTheme and autoDarkTheme
+	autoLightTheme = "light"
+	autoDarkTheme  = "dark"
+
+	defaultBarHeight    = 8.0
+	defaultCornerRadius = 6.0
+	defaultFontFamily   = `"Segoe UI", "Noto Sans", Helvetica, Arial, sans-serif`
+
+	minTextContrast = 4.5 // WCAG AA for normal text
+	minBarHeight    = 2.0
+	maxBarHeight    = 16.0
+	maxCornerRadius = 20.0
+
+	themesFileEnv = "THEMES_FILE"
+)
+
+//go:embed themes.json
+var themesJSON []byte
+
+// fontFamilyPattern allows comma-separated font names, optionally quoted, and nothing that could escape the style block.
+var fontFamilyPattern = regexp.MustCompile(`^[A-Za-z0-9 ,"'-]+$`)
 
 type Theme struct {
-	Background    string
-	Border        string
-	Text          string
-	SecondaryText string
-}
-
-// themes contains predefined colour schemes for SVG generation.
-var themes = map[string]Theme{
-	"dark": {
-		Background:    "#0D1117",
-		Border:        "#2F353D",
-		Text:          "#F0F6FC",
-		SecondaryText: "#9198A1",
-	},
-	"soft-dark": {
-		Background:    "#212830",
-		Border:        "#353C44",
-		Text:          "#D1D7E0",
-		SecondaryText: "#9198A1",
-	},
-	"light": {
-		Background:    "#FFFFFF",
-		Border:        "#DFE4E9",
-		Text:          "#1F2328",
-		SecondaryText: "#59636E",
-	},
+	Background    string  `yaml:"background"`
+	Border        string  `yaml:"border"`
+	Text          string  `yaml:"text"`
+	SecondaryText string  `yaml:"secondary_text"`
+	Other         string  `yaml:"other"`       // Colour of the "Other" entry, empty to keep the language colour
+	Divider       string  `yaml:"divider"`     // Gap between bar segments, defaults to Background
+	BarHeight     float64 `yaml:"-"`           // Height of the stacked bar, decoded through themeFile
+	CornerRadius  float64 `yaml:"-"`           // Corner radius of the card, decoded through themeFile
+	FontFamily    string  `yaml:"font_family"` // CSS font-family list
+
+	Dark *Theme `yaml:"-"` // Palette used when the viewer prefers a dark colour scheme, nil for single-scheme themes
+}
+
+// themeFile is a theme as written in a themes file, with dimensions left nil when their key is absent
+// so an explicit zero isn't mistaken for a missing value.
+type themeFile struct {
+	Theme        `yaml:",inline"`
+	BarHeight    *float64 `yaml:"bar_height"`
+	CornerRadius *float64 `yaml:"corner_radius"`
+}
+
+// fallbackTheme is the dark theme, used for every theme when the embedded ones fail to load.
+var fallbackTheme = Theme{
+	Background:    "#0D1117",
+	Border:        "#2F353D",
+	Text:          "#F0F6FC",
+	SecondaryText: "#9198A1",
+	Divider:       "#0D1117",
+	BarHeight:     defaultBarHeight,
+	CornerRadius:  defaultCornerRadius,
+	FontFamily:    defaultFontFamily,
+}
+
+var themes = embedded[map[string]Theme]{
+	name:     "themes",
+	load:     loadThemes,
+	fallback: map[string]Theme{autoLightTheme: fallbackTheme, autoDarkTheme: fallbackTheme},
+}
+
+// registry returns the predefined themes.
+func registry() map[string]Theme {
+	return themes.get()
+}
+
+// loadThemes returns the embedded themes, extended or overridden by the YAML or JSON file at $THEMES_FILE.
+// An invalid external file is logged and ignored.
+func loadThemes() (map[string]Theme, error) {
+	loaded, err := parseThemes(themesJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range []string{autoLightTheme, autoDarkTheme} {
+		if _, exists := loaded[name]; !exists {
+			return nil, fmt.Errorf("missing theme %q", name)
+		}
+	}
+
+	path := os.Getenv(themesFileEnv)
+	if path == "" {
+		return loaded, nil
+	}
+
+	external, err := loadThemesFile(path)
+	if err != nil {
+		log.Printf("Warning: Failed to load themes from %s, using embedded themes only: %v", path, err)
+		return loaded, nil
+	}
+
+	for name, theme := range external {
+		loaded[name] = theme
+	}
+
+	return loaded, nil
+}
+
+func loadThemesFile(path string) (map[string]Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read themes file: %w", err)
+	}
+
+	return parseThemes(data)
+}
+
+// parseThemes decodes a YAML or JSON theme file, then validates each theme and fills in optional fields.
+func parseThemes(data []byte) (map[string]Theme, error) {
+	var files map[string]themeFile
+	if err := yaml.Unmarshal(data, &files); err != nil {
+		return nil, fmt.Errorf("failed to decode themes: %w", err)
+	}
+
+	if len(files) == 0 {
+		return nil, errors.New("no themes defined")
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parsed := make(map[string]Theme, len(files))
+	var errs []error
+	for _, name := range names {
+		file := files[name]
+		file.Theme.BarHeight, file.Theme.CornerRadius = defaultBarHeight, defaultCornerRadius
+
+		theme, err := file.Theme.withDimensions(file.BarHeight, file.CornerRadius).normalise()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("theme %q: %w", name, err))
+			continue
+		}
+		parsed[name] = theme
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return parsed, nil
+}
+
+// normalise validates the theme's colours, contrast and dimensions, and fills in defaults for optional colours and fonts.
+func (t Theme) normalise() (Theme, error) {
+	required := []struct {
+		key    string
+		colour *string
+	}{
+		{"background", &t.Background},
+		{"border", &t.Border},
+		{"text", &t.Text},
+		{"secondary_text", &t.SecondaryText},
+	}
+
+	for _, field := range required {
+		if *field.colour == "" {
+			return t, fmt.Errorf("missing required key %q", field.key)
+		}
+	}
+
+	if t.Divider == "" {
+		t.Divider = t.Background
+	}
+
+	optional := []struct {
+		key    string
+		colour *string
+	}{
+		{"other", &t.Other},
+		{"divider", &t.Divider},
+	}
+
+	for _, field := range append(required, optional...) {
+		if *field.colour == "" {
+			continue
+		}
+
+		colour, err := ParseColour(*field.colour)
+		if err != nil {
+			return t, fmt.Errorf("%s: %w", field.key, err)
+		}
+		*field.colour = colour
+	}
+
+	for _, field := range []struct {
+		key    string
+		colour string
+	}{
+		{"text", t.Text},
+		{"secondary_text", t.SecondaryText},
+	} {
+		if ratio := contrastRatio(mustParseHex(field.colour), mustParseHex(t.Background)); ratio < minTextContrast {
+			return t, fmt.Errorf("%s contrast against background is %.2f:1, below %.1f:1", field.key, ratio, minTextContrast)
+		}
+	}
+
+	if t.BarHeight < minBarHeight || t.BarHeight > maxBarHeight {
+		return t, fmt.Errorf("bar_height must be between %g and %g", minBarHeight, maxBarHeight)
+	}
+
+	if t.CornerRadius < 0 || t.CornerRadius > maxCornerRadius {
+		return t, fmt.Errorf("corner_radius must be between 0 and %g", maxCornerRadius)
+	}
+
+	if t.FontFamily == "" {
+		t.FontFamily = defaultFontFamily
+	}
+	if !fontFamilyPattern.MatchString(t.FontFamily) {
+		return t, fmt.Errorf("font_family %q contains unsupported characters", t.FontFamily)
+	}
+
+	return t, nil
 }
 
 // GetTheme returns the theme configuration for the given name.
+// AutoTheme returns the light theme paired with the dark one.
 // Falls back to DefaultTheme if the requested theme doesn't exist.
 func GetTheme(name string) Theme {
-	if theme, exists := themes[name]; exists {
+	if name == AutoTheme {
+		light, dark := registry()[autoLightTheme], registry()[autoDarkTheme]
+		light.Dark = &dark
+		return light
+	}
+
+	if theme, exists := registry()[name]; exists {
 		return theme
 	}
 
-	return themes[DefaultTheme]
+	if name != "" {
+		log.Printf("Warning: Unknown theme %q, falling back to %q", name, DefaultTheme)
+	}
+
+	return registry()[DefaultTheme]
+}
+
+// withOverrides returns the theme with every valid colour in overrides layered on top, normalised by ParseColour.
+// Empty or invalid overrides keep the theme's colour. Overrides apply to both palettes of an auto theme.
+func (t Theme) withOverrides(overrides Theme) Theme {
+	if t.Dark != nil {
+		dark := t.Dark.withOverrides(overrides)
+		t.Dark = &dark
+	}
+
+	// Keep the divider matching the background unless the theme sets its own
+	if t.Divider == t.Background {
+		if colour, err := ParseColour(overrides.Background); err == nil {
+			t.Divider = colour
+		}
+	}
+
+	for _, field := range []struct {
+		dst *string
+		src string
+	}{
+		{&t.Background, overrides.Background},
+		{&t.Border, overrides.Border},
+		{&t.Text, overrides.Text},
+		{&t.SecondaryText, overrides.SecondaryText},
+		{&t.Other, overrides.Other},
+	} {
+		if colour, err := ParseColour(field.src); err == nil {
+			*field.dst = colour
+		}
+	}
+
+	return t
+}
+
+// backgrounds returns the background of every palette in the theme.
+func (t Theme) backgrounds() []string {
+	if t.Dark != nil {
+		return []string{t.Background, t.Dark.Background}
+	}
+
+	return []string{t.Background}
+}
+
+// withDimensions returns the theme with the given bar height and corner radius, when set.
+func (t Theme) withDimensions(barHeight, cornerRadius *float64) Theme {
+	if barHeight != nil {
+		t.BarHeight = *barHeight
+	}
+	if cornerRadius != nil {
+		t.CornerRadius = *cornerRadius
+	}
+
+	return t
 }
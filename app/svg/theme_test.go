@@ -1,10 +1,15 @@
 package svg
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
 
 func TestGetTheme_NonExistingTheme(t *testing.T) {
 	theme := GetTheme("nonexistent")
-	expected := themes[DefaultTheme]
+	expected := registry()[DefaultTheme]
 
 	if theme != expected {
 		t.Errorf("GetTheme('nonexistent') = %+v, want default theme %+v", theme, expected)
@@ -13,7 +18,7 @@ func TestGetTheme_NonExistingTheme(t *testing.T) {
 
 func TestGetTheme_EmptyString(t *testing.T) {
 	theme := GetTheme("")
-	expected := themes[DefaultTheme]
+	expected := registry()[DefaultTheme]
 
 	if theme != expected {
 		t.Errorf("GetTheme('') = %+v, want default theme %+v", theme, expected)
@@ -21,15 +26,15 @@ func TestGetTheme_EmptyString(t *testing.T) {
 }
 
 func TestDefaultThemeExists(t *testing.T) {
-	if _, exists := themes[DefaultTheme]; !exists {
+	if _, exists := registry()[DefaultTheme]; !exists {
 		t.Errorf("Default theme '%s' doesn't exist in themes map", DefaultTheme)
 	}
 }
 
 func TestAllPredefinedThemes(t *testing.T) {
-	for name := range themes {
+	for name := range registry() {
 		t.Run(name, func(t *testing.T) {
-			theme := themes[name]
+			theme := registry()[name]
 
 			if theme.Background == "" {
 				t.Errorf("'%s' has empty Background", name)
@@ -45,6 +50,156 @@ func TestAllPredefinedThemes(t *testing.T) {
 			if theme.SecondaryText == "" {
 				t.Errorf("'%s' has empty SecondaryText", name)
 			}
+
+			if theme.Divider == "" || theme.BarHeight == 0 || theme.CornerRadius == 0 || theme.FontFamily == "" {
+				t.Errorf("'%s' is missing optional defaults: %+v", name, theme)
+			}
 		})
 	}
 }
+
+func TestThemeWithOverrides(t *testing.T) {
+	theme := registry()["dark"].withOverrides(Theme{
+		Background: "fff",
+		Text:       "navy",
+		Border:     "invalid",
+		Other:      "#123456",
+	})
+
+	expected := registry()["dark"]
+	expected.Background = "#FFFFFF"
+	expected.Divider = "#FFFFFF"
+	expected.Text = "#000080"
+	expected.Other = "#123456"
+
+	if theme != expected {
+		t.Errorf("withOverrides() = %+v, want %+v", theme, expected)
+	}
+}
+
+func TestGetTheme_Auto(t *testing.T) {
+	theme := GetTheme(AutoTheme)
+
+	if theme.Dark == nil {
+		t.Fatal("GetTheme(auto).Dark = nil, want the dark palette")
+	}
+
+	light, dark := registry()[autoLightTheme], registry()[autoDarkTheme]
+	if theme.Background != light.Background || theme.Text != light.Text {
+		t.Errorf("GetTheme(auto) = %+v, want the %q palette", theme, autoLightTheme)
+	}
+	if *theme.Dark != dark {
+		t.Errorf("GetTheme(auto).Dark = %+v, want %+v", *theme.Dark, dark)
+	}
+}
+
+func TestThemeWithOverrides_Auto(t *testing.T) {
+	theme := GetTheme(AutoTheme).withOverrides(Theme{Text: "red"})
+
+	if theme.Text != "#FF0000" || theme.Dark.Text != "#FF0000" {
+		t.Errorf("withOverrides() text = %q and %q, want #FF0000 in both palettes", theme.Text, theme.Dark.Text)
+	}
+	if registry()[autoDarkTheme].Text == "#FF0000" {
+		t.Error("withOverrides() modified the registered dark theme")
+	}
+}
+
+func TestParseThemes_YAML(t *testing.T) {
+	data := []byte(`
+solarized:
+  background: fdf6e3
+  border: "#93A1A1"
+  text: "#073642"
+  secondary_text: "#586E75"
+  divider: eee8d5
+  bar_height: 10
+  corner_radius: 0.5
+  font_family: "'Fira Sans', sans-serif"
+`)
+
+	parsed, err := parseThemes(data)
+	if err != nil {
+		t.Fatalf("parseThemes() error = %v", err)
+	}
+
+	expected := Theme{
+		Background:    "#FDF6E3",
+		Border:        "#93A1A1",
+		Text:          "#073642",
+		SecondaryText: "#586E75",
+		Divider:       "#EEE8D5",
+		BarHeight:     10,
+		CornerRadius:  0.5,
+		FontFamily:    "'Fira Sans', sans-serif",
+	}
+
+	if parsed["solarized"] != expected {
+		t.Errorf("parseThemes() = %+v, want %+v", parsed["solarized"], expected)
+	}
+}
+
+func TestParseThemes_Dimensions(t *testing.T) {
+	data := []byte(`{
+		"square": {"background": "#000", "border": "#111", "text": "#FFF", "secondary_text": "#EEE", "corner_radius": 0},
+		"plain": {"background": "#000", "border": "#111", "text": "#FFF", "secondary_text": "#EEE"}
+	}`)
+
+	parsed, err := parseThemes(data)
+	if err != nil {
+		t.Fatalf("parseThemes() error = %v", err)
+	}
+
+	if radius := parsed["square"].CornerRadius; radius != 0 {
+		t.Errorf("square corner radius = %v, want the explicit 0", radius)
+	}
+	if plain := parsed["plain"]; plain.CornerRadius != defaultCornerRadius || plain.BarHeight != defaultBarHeight {
+		t.Errorf("plain dimensions = %v and %v, want the defaults", plain.BarHeight, plain.CornerRadius)
+	}
+}
+
+func TestParseThemes_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"Not YAML or JSON", `{invalid`, "decode"},
+		{"Empty", `{}`, "no themes"},
+		{"Missing key", `{"t": {"background": "#000", "border": "#111", "text": "#FFF"}}`, "secondary_text"},
+		{"Invalid colour", `{"t": {"background": "black;}", "border": "#111", "text": "#FFF", "secondary_text": "#EEE"}}`, "background"},
+		{"Low contrast", `{"t": {"background": "#000", "border": "#111", "text": "#FFF", "secondary_text": "#333"}}`, "contrast"},
+		{"Zero bar height", `{"t": {"background": "#000", "border": "#111", "text": "#FFF", "secondary_text": "#EEE", "bar_height": 0}}`, "bar_height"},
+		{"Bar too tall", `{"t": {"background": "#000", "border": "#111", "text": "#FFF", "secondary_text": "#EEE", "bar_height": 40}}`, "bar_height"},
+		{"Unsafe font", `{"t": {"background": "#000", "border": "#111", "text": "#FFF", "secondary_text": "#EEE", "font_family": "x;} svg {display:none"}}`, "font_family"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseThemes([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseThemes() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadThemesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "themes.json")
+	data := `{"mono": {"background": "white", "border": "gray", "text": "black", "secondary_text": "#444"}}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := loadThemesFile(path)
+	if err != nil {
+		t.Fatalf("loadThemesFile() error = %v", err)
+	}
+
+	if parsed["mono"].Background != "#FFFFFF" {
+		t.Errorf("mono background = %s, want #FFFFFF", parsed["mono"].Background)
+	}
+
+	if _, err := loadThemesFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("loadThemesFile() expected error for missing file")
+	}
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"go-readme-stats/scripts"
+)
+
+func main() {
+	if err := scripts.FetchBytesPerLine(); err != nil {
+		log.Fatalf("Failed to fetch bytes per line: %v", err)
+	}
+	fmt.Println("Successfully fetched bytes per line.")
+}
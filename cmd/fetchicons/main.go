@@ -0,0 +1,15 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"go-readme-stats/scripts"
+)
+
+func main() {
+	if err := scripts.FetchLanguageIcons(); err != nil {
+		log.Fatalf("Failed to fetch language icons: %v", err)
+	}
+	fmt.Println("Successfully fetched language icons.")
+}
@@ -0,0 +1,96 @@
+package scripts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+)
+
+const (
+	samplesTreeURL   = "https://api.github.com/repos/github-linguist/linguist/git/trees/main?recursive=1"
+	sampleURL        = "https://raw.githubusercontent.com/github-linguist/linguist/main/%s"
+	bytesPerLinePath = "app/stats/bytes_per_line.json"
+	minSampleLines   = 100 // Languages with fewer sample lines are left to the default factor
+)
+
+// FetchBytesPerLine measures the average bytes per line of every language with a colour across
+// linguist's code samples, and writes the factors to JSON rounded to whole bytes.
+func FetchBytesPerLine() error {
+	colours, err := os.ReadFile(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to read colours: %v", err)
+	}
+
+	var languages map[string]string
+	if err := json.Unmarshal(colours, &languages); err != nil {
+		return fmt.Errorf("failed to unmarshal colours: %v", err)
+	}
+
+	body, err := fetch(samplesTreeURL)
+	if err != nil {
+		return fmt.Errorf("failed to fetch samples tree: %v", err)
+	}
+
+	var tree struct {
+		Tree []struct {
+			Path string `json:"path"`
+			Type string `json:"type"`
+		} `json:"tree"`
+		Truncated bool `json:"truncated"`
+	}
+	if err := json.Unmarshal(body, &tree); err != nil {
+		return fmt.Errorf("failed to unmarshal samples tree: %v", err)
+	}
+	if tree.Truncated {
+		return fmt.Errorf("samples tree is truncated")
+	}
+
+	totalBytes, totalLines := make(map[string]int), make(map[string]int)
+	for _, entry := range tree.Tree {
+		// Samples live at samples/<language>/<file>, special file names in a nested "filenames" directory
+		parts := strings.Split(entry.Path, "/")
+		if entry.Type != "blob" || len(parts) != 3 || parts[0] != "samples" {
+			continue
+		}
+
+		language := parts[1]
+		if _, exists := languages[language]; !exists {
+			continue
+		}
+
+		sample, err := fetch(fmt.Sprintf(sampleURL, entry.Path))
+		if err != nil {
+			return fmt.Errorf("failed to fetch sample %s: %v", entry.Path, err)
+		}
+
+		totalBytes[language] += len(sample)
+		totalLines[language] += countLines(sample)
+	}
+
+	factors := make(map[string]int)
+	for language, lines := range totalLines {
+		if lines >= minSampleLines {
+			factors[language] = int(math.Round(float64(totalBytes[language]) / float64(lines)))
+		}
+	}
+
+	jsonData, err := json.MarshalIndent(factors, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %v", err)
+	}
+
+	return os.WriteFile(bytesPerLinePath, jsonData, 0644)
+}
+
+// countLines returns the number of lines in a file, counting a final line without a newline.
+func countLines(data []byte) int {
+	lines := bytes.Count(data, []byte("\n"))
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		lines++
+	}
+
+	return lines
+}
@@ -0,0 +1,141 @@
+package scripts
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	iconsDataURL    = "https://raw.githubusercontent.com/simple-icons/simple-icons/develop/data/simple-icons.json"
+	iconURL         = "https://raw.githubusercontent.com/simple-icons/simple-icons/develop/icons/%s.svg"
+	iconsOutputPath = "app/svg/icons.json"
+)
+
+var iconPathPattern = regexp.MustCompile(`<path d="([^"]+)"`)
+
+// simpleIcon is an entry of the Simple Icons metadata file.
+type simpleIcon struct {
+	Title   string `json:"title"`
+	Slug    string `json:"slug"`
+	Aliases struct {
+		Aka []string `json:"aka"`
+	} `json:"aliases"`
+}
+
+// FetchLanguageIcons downloads Simple Icons (CC0) for every linguist language that has one and writes
+// their SVG paths to JSON, keyed by linguist name.
+func FetchLanguageIcons() error {
+	languages, err := fetchLanguageNames()
+	if err != nil {
+		return err
+	}
+
+	body, err := fetch(iconsDataURL)
+	if err != nil {
+		return fmt.Errorf("failed to fetch icon data: %v", err)
+	}
+
+	var icons []simpleIcon
+	if err := json.Unmarshal(body, &icons); err != nil {
+		return fmt.Errorf("failed to unmarshal icon data: %v", err)
+	}
+
+	slugs := make(map[string]string)
+	for _, icon := range icons {
+		slug := icon.Slug
+		if slug == "" {
+			slug = titleToSlug(icon.Title)
+		}
+
+		for _, name := range append([]string{icon.Title}, icon.Aliases.Aka...) {
+			if _, exists := slugs[strings.ToLower(name)]; !exists {
+				slugs[strings.ToLower(name)] = slug
+			}
+		}
+	}
+
+	paths := make(map[string]string)
+	for _, language := range languages {
+		slug, exists := slugs[strings.ToLower(language)]
+		if !exists {
+			continue
+		}
+
+		svg, err := fetch(fmt.Sprintf(iconURL, slug))
+		if err != nil {
+			return fmt.Errorf("failed to fetch icon for %s: %v", language, err)
+		}
+
+		match := iconPathPattern.FindSubmatch(svg)
+		if match == nil {
+			return fmt.Errorf("icon for %s has no path", language)
+		}
+		paths[language] = string(match[1])
+	}
+
+	jsonData, err := json.MarshalIndent(paths, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %v", err)
+	}
+
+	return os.WriteFile(iconsOutputPath, jsonData, 0644)
+}
+
+// fetchLanguageNames returns the names of all languages known to linguist.
+func fetchLanguageNames() ([]string, error) {
+	body, err := fetch(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch YAML: %v", err)
+	}
+
+	var data map[string]any
+	if err := yaml.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal YAML: %v", err)
+	}
+
+	names := make([]string, 0, len(data))
+	for name := range data {
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+// fetch downloads the body at the given URL, failing on non-200 responses.
+func fetch(target string) ([]byte, error) {
+	resp, err := http.Get(target)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// titleToSlug derives an icon's file name from its title, following the Simple Icons convention
+// apart from folding accented letters, which no language name needs.
+func titleToSlug(title string) string {
+	replacer := strings.NewReplacer("+", "plus", ".", "dot", "&", "and", "đ", "d", "ħ", "h", "ı", "i", "ĸ", "k", "ŀ", "l", "ł", "l", "ß", "ss", "ŧ", "t")
+	slug := replacer.Replace(strings.ToLower(title))
+
+	var b strings.Builder
+	for _, r := range slug {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}